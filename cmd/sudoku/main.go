@@ -4,38 +4,140 @@ import (
 	"sudoku-csp/solver"
 	"sudoku-csp/sudoku"
 	"time"
+	"flag"
 	"fmt"
+	"os"
+	"strings"
 )
 
 func main() {
-	const NUM_ROWS  = 4
-	const NUM_COLS  = 4
-	const NUM_HINTS = 130
-	const SOLVE_TIME_LIMIT = time.Minute * 2
+	boxRows   := flag.Int("box-rows", 4, "rows per box")
+	boxCols   := flag.Int("box-cols", 4, "columns per box")
+	numHints  := flag.Int("hints", 130, "givens left on the generated board")
+	seed      := flag.Uint64("seed", 0, "generator seed, 0 picks a random one")
+	timeLimit := flag.Duration("time", 2 * time.Minute, "solve time limit")
+	strategy  := flag.String("strategy", "lcv-forward", "solver configuration, auto to pick one from the rules, all to run every one")
+	rulesPath := flag.String("rules", "", "rule table for --strategy=auto, JSON (default built in)")
+	record    := flag.String("record", "", "append a benchmark record of each run to this file")
+	train     := flag.String("train", "", "print a rule table retrained from this benchmark record file and exit")
+	varSpec   := flag.String("var", "", "variable selector spec, overrides the strategy's (one of " + strings.Join(solver.VarSelectorNames(), ", ") + ")")
+	valSpec   := flag.String("val", "", "value selector spec, overrides the strategy's (one of " + strings.Join(solver.ValSelectorNames(), ", ") + ")")
+	checkSpec := flag.String("checker", "", "consistency checker spec, overrides the strategy's (one of " + strings.Join(solver.CheckerNames(), ", ") + ")")
+	flag.Parse()
 
-	fmt.Println("Creating sudoku board...")
-	board   := sudoku.NewBoardFromSolved(NUM_ROWS, NUM_COLS, NUM_HINTS)
-	network := sudoku.NewNetworkFromBoard(board)
-	trail   := solver.NewTrail()
-	
-	varSelector := solver.MRV{}
-	valSelector := solver.LeastConstrainingValue{}
-	checker     := solver.ForwardChecking{}
+	if *train != "" {
+		exitOn(retrain(*train))
+		return
+	}
+
+	rules := solver.DefaultRules
+	if *rulesPath != "" {
+		file, err := os.Open(*rulesPath)
+		exitOn(err)
+
+		rules, err = solver.ReadRuleTable(file)
+		file.Close()
+		exitOn(err)
+	}
 
-	solver := solver.NewBacktrackSolver(network, trail, varSelector, valSelector, checker)
+	fmt.Println("Creating sudoku board...")
+	if *seed == 0 {
+		*seed = solver.RandomSeed()
+	}
+	board    := sudoku.NewBoardFromSolvedWithSeed(*seed, *boxRows, *boxCols, *numHints)
+	features := sudoku.FeaturesOf(board)
 
 	fmt.Println("Done!")
+	fmt.Printf("seed: %d\n", board.Seed)
 	fmt.Printf("starting board:\n%v\n", board.String())
+	fmt.Printf("features: %v\n", features)
+
+	names := []string{*strategy}
+	switch *strategy {
+	case "auto":
+		names = []string{rules.Select(features)}
+	case "all":
+		names = solver.ConfigurationNames()
+	}
+
+	for _, name := range names {
+		configuration, err := solver.ConfigurationNamed(name)
+		exitOn(err)
+
+		configuration, err = override(configuration, *varSpec, *valSpec, *checkSpec)
+		exitOn(err)
+
+		network := sudoku.NewNetworkFromBoard(board)
+		solver  := configuration.NewSolver(network, solver.NewTrail())
 
-	start  := time.Now()
-	result := solver.Solve(SOLVE_TIME_LIMIT)
-	after  := time.Now()
+		fmt.Printf("\nstrategy: %s\n", configuration.Name)
 
-	board = sudoku.NewBoardFromNetwork(network, NUM_ROWS, NUM_COLS)
-	if solver.HasSolution {
-		fmt.Printf("final board:\n%v\n", board.String())
+		start  := time.Now()
+		result := solver.Solve(*timeLimit)
+		after  := time.Now()
+
+		solved := sudoku.NewBoardFromNetwork(network, *boxRows, *boxCols)
+		if solver.HasSolution {
+			fmt.Printf("final board:\n%v\n", solved.String())
+		}
+
+		fmt.Printf("solution found: %v\n", result)
+		fmt.Printf("solving time elapsed: %v\n", after.Sub(start))
+		fmt.Printf("search stats: %v\n", solver.Stats)
+
+		if *record != "" {
+			exitOn(appendRecord(*record, configuration.Name, features, after.Sub(start), result))
+		}
 	}
+}
 
-	fmt.Printf("solution found: %v\n", result)
-	fmt.Printf("solving time elapsed: %v\n", after.Sub(start))
+// swap in the parts given by spec, the name records which were swapped
+func override(configuration solver.Configuration, varSpec, valSpec, checkSpec string) (solver.Configuration, error) {
+	var err error
+
+	if varSpec != "" {
+		configuration.Name += " var=" + varSpec
+		if configuration.VarSelector, err = solver.NewVarSelector(varSpec); err != nil { return configuration, err }
+	}
+	if valSpec != "" {
+		configuration.Name += " val=" + valSpec
+		if configuration.ValSelector, err = solver.NewValSelector(valSpec); err != nil { return configuration, err }
+	}
+	if checkSpec != "" {
+		configuration.Name += " checker=" + checkSpec
+		if configuration.ConsistencyChecker, err = solver.NewChecker(checkSpec); err != nil { return configuration, err }
+	}
+
+	return configuration, nil
+}
+
+func appendRecord(path, name string, features solver.Features, elapsed time.Duration, solved bool) error {
+	file, err := os.OpenFile(path, os.O_APPEND | os.O_CREATE | os.O_WRONLY, 0o644)
+	if err != nil { return err }
+	defer file.Close()
+
+	return solver.WriteBenchmarkRecord(file, solver.BenchmarkRecord{
+		Configuration: name,
+		Features:      features,
+		Elapsed:       elapsed,
+		Solved:        solved,
+	})
+}
+
+func retrain(path string) error {
+	file, err := os.Open(path)
+	if err != nil { return err }
+	defer file.Close()
+
+	records, err := solver.ReadBenchmarkRecords(file)
+	if err != nil { return err }
+
+	return solver.TrainRules(records).Write(os.Stdout)
+}
+
+func exitOn(err error) {
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
 }
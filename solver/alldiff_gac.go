@@ -0,0 +1,208 @@
+package solver
+
+/*
+Generalized arc consistency for AllDiff (Régin's matching filter)
+
+A value stays in a variable's domain only if some maximum matching of the
+variable/value graph uses that edge. With matching edges oriented variable -> value
+and the rest value -> variable, an unmatched edge can appear in a maximum matching
+iff both ends sit in the same strongly connected component, or its value is
+reachable from a value no variable is matched to.
+*/
+type AllDiffGAC struct{}
+
+func (AllDiffGAC) Priority() Priority { return PriorityExpensive }
+func (AllDiffGAC) Idempotent() bool   { return true }
+
+func (AllDiffGAC) Propagate(constraint Constraint, p *Propagation) Outcome {
+	allDiff, ok := constraint.(*AllDiffConstraint)
+	if !ok { return Consistent }
+
+	graph := newMatchingGraph(allDiff.variables)
+	if !graph.match() { return Wipeout }
+
+	for _, removal := range graph.unsupported() {
+		if !p.Remove(removal.variable, removal.value) { return Wipeout }
+	}
+
+	return Consistent
+}
+
+type pruning struct {
+	variable *Variable
+	value    int
+}
+
+// bipartite variable/value graph of one AllDiff
+type matchingGraph struct {
+	variables []*Variable
+	values    []int
+	valueOf   map[int]int
+	varEdges  [][]int // variable -> value indices
+	valEdges  [][]int // value -> variable indices
+	matchVar  []int
+	matchVal  []int
+}
+
+func newMatchingGraph(variables []*Variable) *matchingGraph {
+	g := &matchingGraph{
+		variables: variables,
+		valueOf:   map[int]int{},
+		varEdges:  make([][]int, len(variables)),
+		matchVar:  make([]int, len(variables)),
+	}
+
+	for x, variable := range variables {
+		for _, value := range variable.Values() {
+			index, seen := g.valueOf[value]
+			if !seen {
+				index = len(g.values)
+				g.valueOf[value] = index
+				g.values   = append(g.values, value)
+				g.valEdges = append(g.valEdges, nil)
+			}
+
+			g.varEdges[x]     = append(g.varEdges[x], index)
+			g.valEdges[index] = append(g.valEdges[index], x)
+		}
+	}
+
+	g.matchVal = make([]int, len(g.values))
+	for index := range g.matchVar { g.matchVar[index] = -1 }
+	for index := range g.matchVal { g.matchVal[index] = -1 }
+
+	return g
+}
+
+// maximum matching by augmenting paths, false if some variable can't be matched
+func (g *matchingGraph) match() bool {
+	for x := range g.variables {
+		visited := make([]bool, len(g.values))
+		if !g.augment(x, visited) { return false }
+	}
+
+	return true
+}
+
+func (g *matchingGraph) augment(x int, visited []bool) bool {
+	for _, value := range g.varEdges[x] {
+		if visited[value] { continue }
+		visited[value] = true
+
+		if g.matchVal[value] == -1 || g.augment(g.matchVal[value], visited) {
+			g.matchVar[x], g.matchVal[value] = value, x
+			return true
+		}
+	}
+
+	return false
+}
+
+// Graph for the SCC / reachability pass: nodes 0..n-1 are variables, n.. are values
+
+func (g *matchingGraph) successors(node int) []int {
+	n := len(g.variables)
+
+	if node < n {
+		return []int{n + g.matchVar[node]}
+	}
+
+	value := node - n
+	next  := make([]int, 0, len(g.valEdges[value]))
+	for _, x := range g.valEdges[value] {
+		if g.matchVal[value] != x {
+			next = append(next, x)
+		}
+	}
+
+	return next
+}
+
+// (variable, value) pairs that no maximum matching uses
+func (g *matchingGraph) unsupported() []pruning {
+	n          := len(g.variables)
+	components := g.components()
+
+	// values reachable from an unmatched value
+	reachable := make([]bool, n + len(g.values))
+	queue     := []int{}
+	for value, x := range g.matchVal {
+		if x == -1 {
+			reachable[n + value] = true
+			queue = append(queue, n + value)
+		}
+	}
+	for len(queue) > 0 {
+		node := queue[0]
+		queue = queue[1:]
+
+		for _, next := range g.successors(node) {
+			if reachable[next] { continue }
+
+			reachable[next] = true
+			queue = append(queue, next)
+		}
+	}
+
+	removals := []pruning{}
+	for x, edges := range g.varEdges {
+		for _, value := range edges {
+			if g.matchVar[x] == value { continue }
+			if components[x] == components[n + value] { continue }
+			if reachable[n + value] { continue }
+
+			removals = append(removals, pruning{g.variables[x], g.values[value]})
+		}
+	}
+
+	return removals
+}
+
+// Tarjan's strongly connected components, component id per node
+func (g *matchingGraph) components() []int {
+	size      := len(g.variables) + len(g.values)
+	index     := make([]int, size)
+	lowLink   := make([]int, size)
+	onStack   := make([]bool, size)
+	component := make([]int, size)
+	stack     := []int{}
+	counter   := 1
+	numComps  := 0
+
+	var visit func(node int)
+	visit = func(node int) {
+		index[node], lowLink[node] = counter, counter
+		counter++
+		stack = append(stack, node)
+		onStack[node] = true
+
+		for _, next := range g.successors(node) {
+			if index[next] == 0 {
+				visit(next)
+				lowLink[node] = min(lowLink[node], lowLink[next])
+			} else if onStack[next] {
+				lowLink[node] = min(lowLink[node], index[next])
+			}
+		}
+
+		if lowLink[node] != index[node] { return }
+
+		for {
+			top := stack[len(stack) - 1]
+			stack = stack[:len(stack) - 1]
+			onStack[top] = false
+			component[top] = numComps
+
+			if top == node { break }
+		}
+		numComps++
+	}
+
+	for node := range size {
+		if index[node] == 0 {
+			visit(node)
+		}
+	}
+
+	return component
+}
@@ -0,0 +1,117 @@
+package solver
+
+import "time"
+
+/*
+Incremental solving with assumptions
+
+Push opens a level, Assume / AssumeConstraint add to it, Solve works under
+everything assumed so far, and Pop throws the level away (solution included),
+leaving the network as it was before the Push. Lets callers ask many
+"what if" questions of one Network instead of rebuilding it each time.
+*/
+
+type Assumption struct {
+	Variable *Variable
+	Value    int
+}
+
+type assumptionLevel struct {
+	trailLevel  int
+	constraints int
+	conflict    bool
+}
+
+// open a new assumption level
+func (bt *BacktrackSolver) Push() {
+	bt.levels = append(bt.levels, assumptionLevel{
+		trailLevel:  bt.Trail.Level(),
+		constraints: len(bt.Network.constraints),
+		conflict:    bt.inConflict(),
+	})
+
+	bt.Trail.PlaceMarker()
+	bt.HasSolution = false
+}
+
+// retract everything since the matching Push
+func (bt *BacktrackSolver) Pop() {
+	if len(bt.levels) == 0 { return }
+
+	level    := bt.levels[len(bt.levels) - 1]
+	bt.levels = bt.levels[:len(bt.levels) - 1]
+
+	bt.Trail.UndoTo(level.trailLevel)
+	bt.Network.truncateConstraints(level.constraints)
+	bt.HasSolution = false
+}
+
+// number of open levels
+func (bt *BacktrackSolver) Depth() int {
+	return len(bt.levels)
+}
+
+/*
+Assume variable = value in the current level and propagate, false when that
+contradicts what's already there. After a failed assumption Solve reports no
+solution until the level is popped.
+*/
+func (bt *BacktrackSolver) Assume(variable *Variable, value int) bool {
+	if bt.inConflict() { return false }
+
+	if !variable.domain.Contains(value) || (variable.assigned && variable.Assignment() != value) {
+		return bt.markConflict()
+	}
+
+	bt.Trail.Push(variable)
+	variable.AssignValue(value)
+
+	if !bt.Enforce(bt.Network, bt.Trail) {
+		return bt.markConflict()
+	}
+
+	return true
+}
+
+// add constraint for the current level only and propagate it
+func (bt *BacktrackSolver) AssumeConstraint(constraint Constraint) bool {
+	if bt.inConflict() { return false }
+
+	bt.Network.AddConstraint(constraint)
+	for _, variable := range constraint.Variables() {
+		variable.modified = true
+	}
+
+	if !constraint.IsSatisfied() || !bt.Enforce(bt.Network, bt.Trail) {
+		return bt.markConflict()
+	}
+
+	return true
+}
+
+// whether a solution exists under the assumptions, the network is left as it was
+func (bt *BacktrackSolver) SolvableWith(timeLeft time.Duration, assumptions ...Assumption) bool {
+	bt.Stats.Exhausted = NoLimit
+	bt.Push()
+	defer bt.Pop()
+
+	for _, assumption := range assumptions {
+		if !bt.Assume(assumption.Variable, assumption.Value) { return false }
+	}
+
+	return bt.Solve(timeLeft)
+}
+
+// Internal Helpers
+
+func (bt *BacktrackSolver) inConflict() bool {
+	return len(bt.levels) > 0 && bt.levels[len(bt.levels) - 1].conflict
+}
+
+func (bt *BacktrackSolver) markConflict() bool {
+	if len(bt.levels) > 0 {
+		bt.levels[len(bt.levels) - 1].conflict = true
+	}
+
+	return false
+}
@@ -0,0 +1,150 @@
+package solver
+
+import "math/bits"
+
+/*
+Unassigned variables bucketed by domain size
+
+Variables report every change to their size or assigned flag (see
+Variable.resized), including the ones Trail.Undo makes, so the smallest domain is
+found by scanning buckets instead of every variable.
+
+Each bucket is an indexSet over network indices, so the member added to the
+network first comes out in a few word reads instead of a scan of the bucket.
+*/
+type sizeBuckets struct {
+	variables []*Variable
+	buckets   []indexSet
+	scratch   []*Variable // reused by smallest
+}
+
+func newSizeBuckets(variables []*Variable) *sizeBuckets {
+	b := &sizeBuckets{variables: variables}
+
+	for index, variable := range variables {
+		variable.index   = index
+		variable.buckets = b
+		variable.bucket  = -1
+		b.update(variable)
+	}
+
+	return b
+}
+
+// move variable to the bucket of its current size, or out of them once assigned
+func (b *sizeBuckets) update(variable *Variable) {
+	want := -1
+	if !variable.assigned && !variable.outOfScope {
+		want = variable.domain.size
+	}
+
+	if want == variable.bucket { return }
+
+	if variable.bucket >= 0 {
+		b.buckets[variable.bucket].remove(variable.index)
+	}
+
+	if want >= 0 {
+		for len(b.buckets) <= want {
+			b.buckets = append(b.buckets, newIndexSet(len(b.variables)))
+		}
+
+		b.buckets[want].insert(variable.index)
+	}
+
+	variable.bucket = want
+}
+
+// unassigned variables sharing the smallest domain size in network order, nil when all are assigned
+func (b *sizeBuckets) smallest() []*Variable {
+	for _, bucket := range b.buckets {
+		if bucket.empty() { continue }
+
+		b.scratch = b.scratch[:0]
+		bucket.each(func(index int) {
+			b.scratch = append(b.scratch, b.variables[index])
+		})
+
+		return b.scratch
+	}
+
+	return nil
+}
+
+// the member of the smallest bucket added to the network first, nil when all are assigned
+func (b *sizeBuckets) first() *Variable {
+	for _, bucket := range b.buckets {
+		if !bucket.empty() { return b.variables[bucket.min()] }
+	}
+
+	return nil
+}
+
+
+/*
+Set of small non-negative ints as a bitset with summary levels
+
+levels[0] has a bit per index, every level above a bit per non-zero word of the
+one below, up to a single word. Insert and remove touch a word per level at
+most, and min walks down from the top, so both are O(log64 n).
+*/
+type indexSet struct {
+	levels [][]uint64
+}
+
+func newIndexSet(size int) indexSet {
+	set := indexSet{}
+
+	for {
+		words := max((size + 63) / 64, 1)
+		set.levels = append(set.levels, make([]uint64, words))
+
+		if words == 1 { return set }
+		size = words
+	}
+}
+
+func (s indexSet) empty() bool {
+	return s.levels[len(s.levels) - 1][0] == 0
+}
+
+func (s indexSet) insert(index int) {
+	for _, level := range s.levels {
+		word, was := index / 64, level[index / 64]
+		level[word] |= 1 << (index % 64)
+
+		if was != 0 { return }
+		index = word
+	}
+}
+
+func (s indexSet) remove(index int) {
+	for _, level := range s.levels {
+		word := index / 64
+		level[word] &^= 1 << (index % 64)
+
+		if level[word] != 0 { return }
+		index = word
+	}
+}
+
+// smallest member, call only when not empty
+func (s indexSet) min() int {
+	index := 0
+
+	for level := len(s.levels) - 1; level >= 0; level-- {
+		index = index * 64 + bits.TrailingZeros64(s.levels[level][index])
+	}
+
+	return index
+}
+
+// calls visit with every member in increasing order
+func (s indexSet) each(visit func(int)) {
+	for word, bitsLeft := range s.levels[0] {
+		for bitsLeft != 0 {
+			visit(word * 64 + bits.TrailingZeros64(bitsLeft))
+			bitsLeft &= bitsLeft - 1
+		}
+	}
+}
@@ -0,0 +1,266 @@
+package solver
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"slices"
+	"time"
+)
+
+/*
+Search checkpoints
+
+A Checkpoint records a Search's decision stack, the domains it started from and
+the ones it is at, and its statistics, so a long run (counting every 6x6 grid,
+an empty 25x25) can be written to disk and resumed by another process that
+builds the same network. Resuming restores the starting domains, replays the
+decisions through the checker, and refuses to go on if that doesn't land on the
+saved domains.
+
+Bump CheckpointVersion whenever the format changes, older files are rejected
+rather than misread.
+*/
+const CheckpointVersion = 1
+
+type Checkpoint struct {
+	Version   int
+	Saved     time.Time
+	Variables []string        // names in network order, to catch resuming into another network
+	Root      []VariableState // when the search started
+	Current   []VariableState // when the checkpoint was taken
+	Frames    []CheckpointFrame
+	Descend   bool
+	Result    StepResult
+	Stats     Stats
+}
+
+type VariableState struct {
+	Values   []int
+	Assigned bool
+	Modified bool
+}
+
+// one decision point of the search, Variable indexes Checkpoint.Variables
+type CheckpointFrame struct {
+	Variable int
+	Values   []int
+	Next     int
+	Open     bool
+}
+
+// Search side
+
+func (s *Search) Checkpoint() *Checkpoint {
+	network := s.bt.Network
+	index   := make(map[*Variable]int, len(network.variables))
+
+	checkpoint := &Checkpoint{
+		Version:   CheckpointVersion,
+		Saved:     time.Now(),
+		Variables: make([]string, len(network.variables)),
+		Root:      s.root,
+		Current:   captureStates(network.variables),
+		Descend:   s.descend,
+		Result:    s.result,
+		Stats:     s.bt.Stats,
+	}
+
+	for position, variable := range network.variables {
+		index[variable] = position
+		checkpoint.Variables[position] = variable.Name
+	}
+
+	if !s.started {
+		checkpoint.Root = checkpoint.Current
+	}
+
+	for _, frame := range s.frames {
+		checkpoint.Frames = append(checkpoint.Frames, CheckpointFrame{
+			Variable: index[frame.variable],
+			Values:   frame.values,
+			Next:     frame.next,
+			Open:     frame.open,
+		})
+	}
+
+	return checkpoint
+}
+
+/*
+Save a checkpoint to path every interval while Run is going, checked between
+steps along with the clock. A failed save doesn't stop the search, the next one
+tries again, see CheckpointError. An interval of 0 turns it off.
+*/
+func (s *Search) CheckpointEvery(path string, interval time.Duration) {
+	s.checkpointPath, s.checkpointEvery = path, interval
+	s.lastCheckpoint = time.Now()
+}
+
+// error of the last periodic save, nil once one succeeds
+func (s *Search) CheckpointError() error {
+	return s.checkpointErr
+}
+
+func (s *Search) autoCheckpoint(now time.Time) {
+	if s.checkpointEvery <= 0 || now.Sub(s.lastCheckpoint) < s.checkpointEvery { return }
+
+	s.lastCheckpoint = now
+	s.checkpointErr  = s.Checkpoint().Save(s.checkpointPath)
+}
+
+/*
+Continue a checkpointed search on bt, whose network must be built the same way
+as the one checkpointed and not searched yet. bt.Stats picks up the saved
+statistics.
+*/
+func ResumeSearch(bt *BacktrackSolver, checkpoint *Checkpoint) (*Search, error) {
+	network := bt.Network
+
+	if checkpoint.Version != CheckpointVersion {
+		return nil, fmt.Errorf("checkpoint version %d, expected %d", checkpoint.Version, CheckpointVersion)
+	}
+	if !slices.Equal(checkpoint.Variables, variableNames(network.variables)) {
+		return nil, fmt.Errorf("checkpoint is of a different network")
+	}
+	if len(checkpoint.Root) != len(network.variables) || len(checkpoint.Current) != len(network.variables) {
+		return nil, fmt.Errorf("checkpoint domains don't cover the network")
+	}
+
+	// a failed replay puts the network, trail and stats back as they were
+	level, stats := bt.Trail.Level(), bt.Stats
+	var before snapshot
+	before.capture(network.variables)
+
+	fail := func(err error) (*Search, error) {
+		bt.Trail.UndoTo(level)
+		before.restore()
+		bt.Stats = stats
+		return nil, err
+	}
+
+	restoreStates(network.variables, checkpoint.Root)
+
+	search := NewSearch(bt)
+	search.root    = checkpoint.Root
+	search.started = true
+	search.descend = checkpoint.Descend
+	search.result  = checkpoint.Result
+
+	for depth, saved := range checkpoint.Frames {
+		if saved.Variable < 0 || saved.Variable >= len(network.variables) || saved.Next < 0 || saved.Next > len(saved.Values) {
+			return fail(fmt.Errorf("checkpoint frame %d is malformed", depth))
+		}
+		if saved.Open && saved.Next < 1 {
+			return fail(fmt.Errorf("checkpoint frame %d is open without a value tried", depth))
+		}
+
+		// the frame's values were ordered from the domain the replay has reached
+		variable := network.variables[saved.Variable]
+		for _, value := range saved.Values {
+			if !variable.domain.Contains(value) {
+				return fail(fmt.Errorf("checkpoint frame %d tries %d, outside the domain of %s", depth, value, variable.Name))
+			}
+		}
+
+		frame := searchFrame{
+			variable: variable,
+			values:   saved.Values,
+			next:     saved.Next,
+			open:     saved.Open,
+		}
+
+		if frame.open {
+			bt.Trail.PlaceMarker()
+			bt.Trail.Push(frame.variable)
+			frame.variable.AssignValue(frame.values[frame.next - 1])
+
+			if !bt.Enforce(network, bt.Trail) {
+				return fail(fmt.Errorf("replaying %s = %d failed", frame.variable.Name, frame.values[frame.next - 1]))
+			}
+		}
+
+		search.frames = append(search.frames, frame)
+	}
+
+	for index, variable := range network.variables {
+		saved := checkpoint.Current[index]
+
+		if variable.assigned != saved.Assigned || !sameValues(variable.Values(), saved.Values) {
+			return fail(fmt.Errorf("replay disagrees with the checkpoint on %s", variable.Name))
+		}
+	}
+
+	bt.Stats = checkpoint.Stats
+	return search, nil
+}
+
+// File side
+
+// write the checkpoint as JSON, replacing path only once the whole file is written
+func (c *Checkpoint) Save(path string) error {
+	data, err := json.Marshal(c)
+	if err != nil { return err }
+
+	temporary := path + ".tmp"
+	if err := os.WriteFile(temporary, data, 0o644); err != nil { return err }
+
+	return os.Rename(temporary, path)
+}
+
+func LoadCheckpoint(path string) (*Checkpoint, error) {
+	data, err := os.ReadFile(path)
+	if err != nil { return nil, err }
+
+	checkpoint := &Checkpoint{}
+	if err := json.Unmarshal(data, checkpoint); err != nil {
+		return nil, fmt.Errorf("reading checkpoint %s: %w", path, err)
+	}
+
+	if checkpoint.Version != CheckpointVersion {
+		return nil, fmt.Errorf("checkpoint %s has version %d, expected %d", path, checkpoint.Version, CheckpointVersion)
+	}
+
+	return checkpoint, nil
+}
+
+// Internal Helpers
+
+func captureStates(variables []*Variable) []VariableState {
+	states := make([]VariableState, len(variables))
+
+	for index, variable := range variables {
+		states[index] = VariableState{
+			Values:   slices.Clone(variable.Values()),
+			Assigned: variable.assigned,
+			Modified: variable.modified,
+		}
+	}
+
+	return states
+}
+
+// fresh domains, only meant for a network the trail holds nothing of yet
+func restoreStates(variables []*Variable, states []VariableState) {
+	for index, variable := range variables {
+		variable.domain   = NewDomain(states[index].Values...)
+		variable.assigned = states[index].Assigned
+		variable.modified = states[index].Modified
+		variable.resized()
+	}
+}
+
+func variableNames(variables []*Variable) []string {
+	names := make([]string, len(variables))
+	for index, variable := range variables {
+		names[index] = variable.Name
+	}
+
+	return names
+}
+
+func sameValues(left, right []int) bool {
+	if len(left) != len(right) { return false }
+
+	return slices.Equal(slices.Sorted(slices.Values(left)), slices.Sorted(slices.Values(right)))
+}
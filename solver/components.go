@@ -0,0 +1,168 @@
+package solver
+
+import (
+	"math/big"
+	"time"
+)
+
+/*
+Independent components
+
+Unassigned variables that share a constraint are connected; once a network is
+partly solved it often falls apart into groups that don't constrain each other
+at all. Each such component can be searched on its own: a failure in one
+doesn't backtrack through the others, and the number of solutions is the
+product of the components' counts.
+
+While a component is searched the network is scoped to it, the built-in
+variable selectors skip everything outside. Custom VarSelectors have to check
+Network.InScope themselves to take part.
+*/
+
+// connected groups of unassigned variables, each in network order
+func (n *Network) Components() [][]*Variable {
+	component := make(map[*Variable]int, len(n.variables))
+	count     := 0
+
+	for _, start := range n.variables {
+		if start.assigned { continue }
+		if _, seen := component[start]; seen { continue }
+
+		component[start] = count
+		queue := []*Variable{start}
+
+		for len(queue) > 0 {
+			variable := queue[0]
+			queue     = queue[1:]
+
+			for _, neighbor := range n.GetNeighbors(variable) {
+				if neighbor.assigned { continue }
+				if _, seen := component[neighbor]; seen { continue }
+
+				component[neighbor] = count
+				queue = append(queue, neighbor)
+			}
+		}
+
+		count++
+	}
+
+	components := make([][]*Variable, count)
+	for _, variable := range n.variables {
+		if index, ok := component[variable]; ok {
+			components[index] = append(components[index], variable)
+		}
+	}
+
+	return components
+}
+
+// whether selectors may pick variable under the current scope
+func (n *Network) InScope(variable *Variable) bool {
+	return !variable.outOfScope
+}
+
+/*
+Solve each independent component on its own, true if all of them were solved.
+
+Pending changes are propagated first, above a new trail marker that the
+solution is then assigned above (a Trail.Undo takes it all back off). On
+failure the network is left as it was. Stats add up the component searches.
+*/
+func (bt *BacktrackSolver) SolveByComponents(timeLeft time.Duration) bool {
+	deadline := time.Now().Add(timeLeft)
+	total    := Stats{}
+	defer func() { bt.Stats = total }()
+
+	level := bt.Trail.Level()
+	bt.Trail.PlaceMarker()
+	if bt.HasSolution = bt.Enforce(bt.Network, bt.Trail); !bt.HasSolution {
+		bt.Trail.Undo()
+		return false
+	}
+
+	for _, component := range bt.Network.Components() {
+		bt.Network.restrictTo(component)
+		bt.HasSolution = false
+		bt.Solve(time.Until(deadline))
+		total.add(bt.Stats)
+
+		if !bt.HasSolution { break }
+	}
+
+	bt.Network.restrictTo(nil)
+
+	if !bt.HasSolution {
+		bt.Trail.UndoTo(level)
+	}
+
+	return bt.HasSolution
+}
+
+/*
+Count solutions as the product of each component's count.
+
+ok is false when a limit or timeLeft cut a component's enumeration short, the
+count is meaningless then. The network is left as it was; Stats add up the
+component searches (Solutions counts the components' solutions, not the
+product).
+*/
+func (bt *BacktrackSolver) CountByComponents(timeLeft time.Duration) (count *big.Int, ok bool) {
+	deadline := time.Now().Add(timeLeft)
+	total    := Stats{}
+	defer func() { bt.Stats = total }()
+
+	count = big.NewInt(0)
+
+	bt.Trail.PlaceMarker()
+	defer bt.Trail.Undo()
+
+	if !bt.Enforce(bt.Network, bt.Trail) { return count, true }
+
+	count.SetInt64(1)
+	defer bt.Network.restrictTo(nil)
+
+	for _, component := range bt.Network.Components() {
+		bt.Network.restrictTo(component)
+		solutions := bt.SolveAll(time.Until(deadline), nil)
+		total.add(bt.Stats)
+
+		if bt.Stopped() { return count, false }
+
+		count.Mul(count, big.NewInt(int64(solutions)))
+		if solutions == 0 { break }
+	}
+
+	return count, true
+}
+
+// Internal Helpers
+
+// scope selectors to variables, nil lifts the scope
+func (n *Network) restrictTo(variables []*Variable) {
+	n.Finalize()
+
+	inScope := make(map[*Variable]bool, len(variables))
+	for _, variable := range variables {
+		inScope[variable] = true
+	}
+
+	for _, variable := range n.variables {
+		variable.outOfScope = variables != nil && !inScope[variable]
+		variable.resized()
+	}
+}
+
+func (s *Stats) add(other Stats) {
+	s.Nodes      += other.Nodes
+	s.Backtracks += other.Backtracks
+	s.Solutions  += other.Solutions
+	s.MaxTrail    = max(s.MaxTrail, other.MaxTrail)
+	s.Iterations += other.Iterations
+	s.Restarts   += other.Restarts
+	s.Elapsed    += other.Elapsed
+
+	if s.Exhausted == NoLimit {
+		s.Exhausted = other.Exhausted
+	}
+}
@@ -0,0 +1,86 @@
+package solver_test
+
+import (
+	"math/big"
+	"testing"
+	"time"
+	"github.com/stretchr/testify/assert"
+	"sudoku-csp/solver"
+)
+
+// groups independent AllDiffs of size variables over 1..size
+func newGroupsNetwork(groups, size int) *solver.Network {
+	values := make([]int, size)
+	for index := range values {
+		values[index] = index + 1
+	}
+
+	network := solver.NewNetwork()
+	for range groups {
+		variables := make([]*solver.Variable, size)
+		for index := range variables {
+			variables[index] = solver.NewVariable("", values, nil)
+			network.AddVariable(variables[index])
+		}
+		network.AddConstraint(solver.NewAllDiffConstraint(variables))
+	}
+
+	return network
+}
+
+func newComponentSolver(network *solver.Network) *solver.BacktrackSolver {
+	return solver.NewBacktrackSolver(network, solver.NewTrail(), solver.MRV{}, solver.DefaultValOrder{}, solver.ForwardChecking{})
+}
+
+func TestComponents(t *testing.T) {
+	network := newGroupsNetwork(3, 3)
+	components := network.Components()
+
+	assert.Len(t, components, 3)
+	for index, component := range components {
+		assert.Equal(t, network.Variables()[index * 3:index * 3 + 3], component)
+	}
+
+	// assigned variables don't connect anything
+	network.Variables()[0].AssignValue(1)
+	assert.Len(t, network.Components()[0], 2)
+}
+
+func TestCountByComponents(t *testing.T) {
+	// 6^30 overflows an int64
+	bt := newComponentSolver(newGroupsNetwork(30, 3))
+
+	count, ok := bt.CountByComponents(time.Minute)
+	expected  := new(big.Int).Exp(big.NewInt(6), big.NewInt(30), nil)
+
+	assert.True(t, ok)
+	assert.Equal(t, 0, expected.Cmp(count), "expected %v, got %v", expected, count)
+	assert.Equal(t, 30 * 6, bt.Stats.Solutions)
+
+	for _, variable := range bt.Network.Variables() {
+		assert.False(t, variable.Assigned(), "counting left assignments behind")
+	}
+}
+
+func TestSolveByComponents(t *testing.T) {
+	bt := newComponentSolver(newGroupsNetwork(4, 3))
+
+	assert.True(t, bt.SolveByComponents(time.Minute))
+	assert.Empty(t, solver.Verify(bt.Network))
+
+	// one unsolvable group fails the whole network and leaves it untouched
+	network := newGroupsNetwork(2, 3)
+	broken  := solver.NewVariable("", []int{1, 2}, nil)
+	network.AddVariable(broken)
+	network.AddConstraint(solver.NewAllDiffConstraint(network.Variables()[3:5]))
+	network.AddConstraint(solver.NewAllDiffConstraint([]*solver.Variable{broken, network.Variables()[3], network.Variables()[4]}))
+	network.Variables()[3].RemoveValueFromDomain(3)
+	network.Variables()[4].RemoveValueFromDomain(3)
+
+	bt = newComponentSolver(network)
+	assert.False(t, bt.SolveByComponents(time.Minute))
+
+	for _, variable := range network.Variables() {
+		assert.False(t, variable.Assigned())
+	}
+}
@@ -1,5 +1,10 @@
 package solver
 
+import (
+	"fmt"
+	"slices"
+)
+
 // generic CSP constraint interface
 type Constraint interface {
 	Variables()   []*Variable
@@ -52,6 +57,48 @@ func (c *AllDiffConstraint) IsSatisfied() bool {
 	return true
 }
 
+// Propagating: forward checking, assigned values are removed from every other variable
+func (c *AllDiffConstraint) Propagate(p *Propagation) Outcome {
+	unassigned := 0
+
+	for _, variable := range c.variables {
+		if !variable.assigned {
+			unassigned++
+			continue
+		}
+
+		value := variable.Assignment()
+		for _, other := range c.variables {
+			if other == variable { continue }
+
+			if !p.RemoveBecause(other, value, Fact{variable, value, true}) { return Wipeout }
+		}
+	}
+
+	if unassigned == 0 { return Entailed }
+
+	return Consistent
+}
+
+// sorted values still possible in the constraint, and which variables can take each
+func (c *AllDiffConstraint) valueSupports() ([]int, map[int][]*Variable) {
+	candidates := make(map[int][]*Variable)
+
+	for _, variable := range c.variables {
+		for _, value := range variable.Values() {
+			candidates[value] = append(candidates[value], variable)
+		}
+	}
+
+	values := make([]int, 0, len(candidates))
+	for value := range candidates {
+		values = append(values, value)
+	}
+	slices.Sort(values)
+
+	return values, candidates
+}
+
 func (c *AllDiffConstraint) String() string {
 	repr := "{"
 
@@ -63,6 +110,168 @@ func (c *AllDiffConstraint) String() string {
 		repr += variable.String()
 	}
 
-	return repr
+	return repr + "}"
+}
+
+
+
+// Constraint: variable takes one specific value, satisfied while the value is still in its domain
+type EqualsConstraint struct {
+	variable *Variable
+	value    int
+}
+
+func NewEqualsConstraint(variable *Variable, value int) *EqualsConstraint {
+	return &EqualsConstraint{
+		variable: variable,
+		value:    value,
+	}
+}
+
+// Accessors & Constraint Interface
+func (c *EqualsConstraint) Value() int {
+	return c.value
+}
+
+func (c *EqualsConstraint) Variables() []*Variable {
+	return []*Variable{c.variable}
+}
+
+func (c *EqualsConstraint) IsModified() bool {
+	return c.variable.modified
+}
+
+func (c *EqualsConstraint) IsSatisfied() bool {
+	return c.variable.domain.Contains(c.value)
+}
+
+func (c *EqualsConstraint) String() string {
+	return fmt.Sprintf("{%v == %d}", c.variable, c.value)
+}
+
+// Propagating: as a hard constraint it simply assigns the value
+func (c *EqualsConstraint) Propagate(p *Propagation) Outcome {
+	if !p.Assign(c.variable, c.value) { return Wipeout }
+
+	return Entailed
+}
+
+
+// Constraint: left's value is smaller than right's
+type LessThanConstraint struct {
+	left  *Variable
+	right *Variable
+}
+
+func NewLessThanConstraint(left, right *Variable) *LessThanConstraint {
+	return &LessThanConstraint{
+		left:  left,
+		right: right,
+	}
+}
+
+// Accessors & Constraint Interface
+func (c *LessThanConstraint) Variables() []*Variable {
+	return []*Variable{c.left, c.right}
+}
+
+func (c *LessThanConstraint) IsModified() bool {
+	return c.left.modified || c.right.modified
 }
 
+// some value left still has is below some value right still has
+func (c *LessThanConstraint) IsSatisfied() bool {
+	if c.left.domain.Empty() || c.right.domain.Empty() { return false }
+
+	return slices.Min(c.left.Values()) < slices.Max(c.right.Values())
+}
+
+func (c *LessThanConstraint) String() string {
+	return fmt.Sprintf("{%v < %v}", c.left, c.right)
+}
+
+// Propagating: bounds consistency, left loses values from right's maximum up, right values up to left's minimum
+func (c *LessThanConstraint) Propagate(p *Propagation) Outcome {
+	if !c.IsSatisfied() { return Wipeout }
+
+	lowest, highest := slices.Min(c.left.Values()), slices.Max(c.right.Values())
+
+	for _, value := range slices.Clone(c.left.Values()) {
+		if value >= highest && !p.Remove(c.left, value) { return Wipeout }
+	}
+	for _, value := range slices.Clone(c.right.Values()) {
+		if value <= lowest && !p.Remove(c.right, value) { return Wipeout }
+	}
+
+	if slices.Max(c.left.Values()) < slices.Min(c.right.Values()) { return Entailed }
+
+	return Consistent
+}
+
+
+// Constraint: a variable only takes values[i+1] after an earlier one (in variables order) took values[i]
+type PrecedenceConstraint struct {
+	variables []*Variable
+	values    []int
+}
+
+func NewPrecedenceConstraint(variables []*Variable, values []int) *PrecedenceConstraint {
+	return &PrecedenceConstraint{
+		variables: variables,
+		values:    values,
+	}
+}
+
+// Accessors & Constraint Interface
+func (c *PrecedenceConstraint) Variables() []*Variable {
+	return c.variables
+}
+
+func (c *PrecedenceConstraint) IsModified() bool {
+	for _, variable := range c.variables {
+		if variable.modified { return true }
+	}
+
+	return false
+}
+
+// every assigned values[i+1] has an earlier variable that can still take values[i]
+func (c *PrecedenceConstraint) IsSatisfied() bool {
+	for index := 1; index < len(c.values); index++ {
+		earlier := false
+
+		for _, variable := range c.variables {
+			if variable.assigned && variable.Assignment() == c.values[index] && !earlier { return false }
+			earlier = earlier || variable.domain.Contains(c.values[index - 1])
+		}
+	}
+
+	return true
+}
+
+func (c *PrecedenceConstraint) String() string {
+	repr := fmt.Sprintf("{precedence %v:", c.values)
+	for _, variable := range c.variables {
+		repr += " " + variable.String()
+	}
+
+	return repr + "}"
+}
+
+// Propagating: values[i+1] goes from every variable no earlier variable can give values[i]
+func (c *PrecedenceConstraint) Propagate(p *Propagation) Outcome {
+	for index := 1; index < len(c.values); index++ {
+		value, earlier := c.values[index], false
+
+		for _, variable := range c.variables {
+			if !earlier && !p.Remove(variable, value) { return Wipeout }
+			earlier = earlier || variable.domain.Contains(c.values[index - 1])
+		}
+	}
+
+	for _, variable := range c.variables {
+		if !variable.assigned { return Consistent }
+	}
+
+	return Entailed
+}
@@ -0,0 +1,53 @@
+package solver
+
+import (
+	"slices"
+	"time"
+)
+
+// Assumptions that can't all hold at once
+type Core struct {
+	Assumptions []Assumption
+
+	// every deletion check was decided, so dropping any one assumption makes the
+	// rest satisfiable; false when some check hit a limit and its assumption was kept
+	Minimal bool
+}
+
+/*
+Minimal unsatisfiable subset of assumptions, by deletion
+
+Drops one assumption at a time and keeps it dropped whenever the rest still has
+no solution. Each check is a SolvableWith round, so the network is left as it was.
+Returns false when the assumptions are satisfiable together, or when timeLeft
+(or one of the solver's Limits) ran out before that could be decided.
+*/
+func (bt *BacktrackSolver) MinimalCore(timeLeft time.Duration, assumptions []Assumption) (*Core, bool) {
+	deadline := time.Now().Add(timeLeft)
+
+	if bt.SolvableWith(time.Until(deadline), assumptions...) || bt.Stopped() {
+		return nil, false
+	}
+
+	core := &Core{
+		Assumptions: slices.Clone(assumptions),
+		Minimal:     true,
+	}
+
+	for index := 0; index < len(core.Assumptions); {
+		candidate := slices.Delete(slices.Clone(core.Assumptions), index, index + 1)
+		solvable  := bt.SolvableWith(time.Until(deadline), candidate...)
+
+		switch {
+		case bt.Stopped():
+			core.Minimal = false
+			index++
+		case solvable:
+			index++
+		default:
+			core.Assumptions = candidate
+		}
+	}
+
+	return core, true
+}
@@ -0,0 +1,130 @@
+package solver
+
+import "time"
+
+/*
+Discrepancy search
+
+Instead of backtracking depth-first, trust the ValSelector's order and look at
+the paths that stray from it least first. Iteration k of limited discrepancy
+search (LDS) explores the paths that deviate exactly k times, where taking the
+i-th value of a node's order counts as i deviations. Depth-bounded discrepancy
+search (DDS) instead lets iteration k deviate anywhere above depth k, must
+deviate at depth k-1 and follows the order below it.
+
+Each iteration only reports solutions the earlier ones couldn't reach, so
+SolveAll still sees every solution once, provided the ValSelector orders the
+same domains the same way every time. Iterations stop when one ran without
+leaving any value out (the search space is exhausted) or after
+Limits.Discrepancies of them.
+*/
+type SearchMode int
+
+const (
+	DepthFirst SearchMode = iota
+	LimitedDiscrepancy
+	DepthBoundedDiscrepancy
+)
+
+func (m SearchMode) String() string {
+	return [...]string{"depth-first", "limited discrepancy", "depth-bounded discrepancy"}[m]
+}
+
+// iterate discrepancy probes, true once the search should stop (like search)
+func (bt *BacktrackSolver) discrepancySearch(deadline time.Time, onSolution func() bool) bool {
+	for iteration := 0; ; iteration++ {
+		if bt.Limits.Discrepancies > 0 && iteration > bt.Limits.Discrepancies {
+			bt.Stats.Exhausted = DiscrepancyLimit
+			return true
+		}
+
+		bt.Stats.Iterations++
+		skipped := false
+
+		if bt.probe(deadline, onSolution, iteration, 0, 0, &skipped) { return true }
+		if !skipped { return false }
+	}
+}
+
+// depth-first within one iteration's allowance, spent counts LDS deviations taken so far
+func (bt *BacktrackSolver) probe(deadline time.Time, onSolution func() bool, iteration, depth, spent int, skipped *bool) bool {
+	if bt.HasSolution {
+		return true
+	} else if bt.inConflict() {
+		return false
+	} else if bt.exhausted(deadline) {
+		return true
+	}
+
+	variable := bt.Select(bt.Network)
+	if variable == nil {
+		// reached by an earlier iteration already
+		if !bt.lastDeviation(iteration, depth, spent) { return false }
+
+		bt.Stats.Solutions++
+		bt.traceSolution(depth)
+
+		if onSolution == nil {
+			bt.HasSolution = true
+			return true
+		}
+
+		return !onSolution() || bt.exhausted(deadline)
+	}
+
+	values   := bt.OrderValues(variable, bt.Network)
+	from, to := bt.allowance(iteration, depth, spent, len(values))
+
+	// whatever this iteration leaves out is up to the next one
+	if from > 0 || to < len(values) {
+		*skipped = true
+	}
+
+	for index := from; index < to; index++ {
+		bt.Stats.Nodes++
+		bt.Trail.PlaceMarker()
+		bt.Trail.Push(variable)
+		variable.AssignValue(values[index])
+
+		consistent := bt.Enforce(bt.Network, bt.Trail)
+		bt.traceDecide(variable, values[index], depth, consistent)
+
+		if consistent {
+			if bt.probe(deadline, onSolution, iteration, depth + 1, spent + index, skipped) {
+				return true
+			}
+
+			bt.traceBacktrack(variable, values[index], depth)
+		}
+
+		bt.Trail.Undo()
+		bt.Stats.Backtracks++
+	}
+
+	return false
+}
+
+// values[from:to] of a node's order that this iteration may try
+func (bt *BacktrackSolver) allowance(iteration, depth, spent, count int) (from, to int) {
+	if bt.Mode == LimitedDiscrepancy {
+		return 0, min(count, iteration - spent + 1)
+	}
+
+	switch {
+	case depth >= iteration:
+		return 0, min(count, 1)
+	case depth == iteration - 1:
+		return 1, count
+	default:
+		return 0, count
+	}
+}
+
+// whether a path ending here belongs to this iteration rather than an earlier one
+func (bt *BacktrackSolver) lastDeviation(iteration, depth, spent int) bool {
+	if bt.Mode == LimitedDiscrepancy {
+		return spent == iteration
+	}
+
+	return iteration == 0 || depth >= iteration
+}
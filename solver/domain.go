@@ -5,10 +5,32 @@ import (
 	"fmt"
 )
 
-// Represents set of all possible assignable to variable
+/*
+Represents set of all possible assignable to variable
+
+Stored as a reversible sparse set: values[:size] are live, values[size:] hold
+removed values. Removing swaps the value just past the live region, so shrinking
+never moves anything outside values[:size] and restoring an older size brings
+back exactly the values that were live at that point. This is what lets the
+Trail record a size instead of cloning the domain.
+
+positions maps value - offset to the value's index in values (-1 when it was
+never added), so membership, removal and assignment don't scan. Values spread
+wider than a few times their count (say 1 and 1_000_000_000) go to the sparse
+map instead, so a wide domain costs memory by its count and not its span.
+*/
 type Domain struct {
-	values   []int
-	modified bool
+	values    []int
+	positions []int
+	offset    int
+	sparse    map[int]int // replaces positions once the values are too spread out
+	size      int
+	modified  bool
+}
+
+// span positions may cover for count values before the domain switches to sparse
+func denseSpan(count int) int {
+	return 4 * count + 64
 }
 
 func NewDomain(values ...int) *Domain {
@@ -17,56 +39,85 @@ func NewDomain(values ...int) *Domain {
 		modified: false,
 	}
 
-	domain.values = append(domain.values, values...)
+	for _, value := range values {
+		if domain.position(value) != -1 { continue }
+
+		domain.values = append(domain.values, value)
+		domain.index(value, len(domain.values) - 1)
+	}
+	domain.size = len(domain.values)
 
 	return domain
 }
 
 func (d *Domain) Copy() *Domain {
-	domainCopy := &Domain{
-		values:   slices.Clone(d.values),
-		modified: false,
-	}
-
-	return domainCopy
+	return NewDomain(d.values[:d.size]...)
 }
 
 // Accessors
 func (d *Domain) Contains(value int) bool {
-	return slices.Contains(d.values, value)
+	return d.indexOf(value) != -1
 }
 
 func (d *Domain) Size() int {
-	return len(d.values)
+	return d.size
 }
 
 func (d *Domain) Empty() bool {
-	return len(d.values) == 0
+	return d.size == 0
 }
 
 func (d *Domain) Modified() bool {
 	return d.modified
 }
 
+// live values, aliases the domain so callers that mutate while iterating should clone
+func (d *Domain) Values() []int {
+	return d.values[:d.size]
+}
+
 // Mutators
+
+/*
+Add value back, or for the first time
+
+Not reversible: the Trail restores domains by size, and a value moved into the
+live region here can push out one that was live when an entry was recorded.
+Use it while building networks, before anything is trailed.
+*/
 func (d *Domain) Expand(value int) {
-	if !d.Contains(value) {
+	if d.Contains(value) { return }
+
+	index := d.position(value)
+	if index == -1 {
 		d.values = append(d.values, value)
+		index    = len(d.values) - 1
+		d.index(value, index)
 	}
+
+	d.swap(index, d.size)
+	d.size++
 }
 
 // modified -> true when values emptied out
 func (d *Domain) Remove(value int) bool {
-	if d.Empty() || !d.Contains(value) { return false }
+	index := d.indexOf(value)
+	if index == -1 { return false }
 
-	for index, item := range d.values {
-		if item == value {
-			d.values = append(d.values[:index], d.values[index+1:]...)
-			d.modified = true
+	d.size--
+	d.swap(index, d.size)
+	d.modified = true
 
-			break
-		}
-	}
+	return true
+}
+
+// shrink to the single value, false if it isn't live
+func (d *Domain) Assign(value int) bool {
+	index := d.indexOf(value)
+	if index == -1 { return false }
+
+	d.swap(index, 0)
+	d.size = 1
 
 	return true
 }
@@ -75,6 +126,81 @@ func (d *Domain) SetModified(modifer bool) {
 	d.modified = modifer
 }
 
+// Internal Helpers
+
+// index of a live value, -1 if it isn't live
+func (d *Domain) indexOf(value int) int {
+	index := d.position(value)
+	if index >= d.size { return -1 }
+
+	return index
+}
+
+// index of value in values, live or removed, -1 if it was never added
+func (d *Domain) position(value int) int {
+	if d.sparse != nil {
+		index, ok := d.sparse[value]
+		if !ok { return -1 }
+
+		return index
+	}
+
+	slot := value - d.offset
+	if slot < 0 || slot >= len(d.positions) { return -1 }
+
+	return d.positions[slot]
+}
+
+// record value at index, growing positions to cover it or going sparse when that would span too much
+func (d *Domain) index(value, index int) {
+	if d.sparse == nil && len(d.positions) > 0 {
+		low, high := min(d.offset, value), max(d.offset + len(d.positions) - 1, value)
+
+		if high - low >= denseSpan(len(d.values)) {
+			d.sparse = make(map[int]int, len(d.values))
+			for slot, position := range d.positions {
+				if position != -1 { d.sparse[d.offset + slot] = position }
+			}
+			d.positions = nil
+		}
+	}
+
+	if d.sparse != nil {
+		d.sparse[value] = index
+		return
+	}
+
+	if len(d.positions) == 0 {
+		d.offset = value
+	}
+
+	if shift := d.offset - value; shift > 0 {
+		d.positions = append(slices.Repeat([]int{-1}, shift), d.positions...)
+		d.offset    = value
+	}
+
+	for value - d.offset >= len(d.positions) {
+		d.positions = append(d.positions, -1)
+	}
+
+	d.positions[value - d.offset] = index
+}
+
+// point value's position at index, value already recorded
+func (d *Domain) moved(value, index int) {
+	if d.sparse != nil {
+		d.sparse[value] = index
+	} else {
+		d.positions[value - d.offset] = index
+	}
+}
+
+func (d *Domain) swap(left, right int) {
+	d.values[left], d.values[right] = d.values[right], d.values[left]
+	d.moved(d.values[left], left)
+	d.moved(d.values[right], right)
+}
+
 
 /*
 Domain:
@@ -83,9 +209,8 @@ Domain:
 */
 func (d *Domain) String() string {
 	res := "Domain:\n"
-	res += fmt.Sprintf("  values:   %v\n", d.values)
+	res += fmt.Sprintf("  values:   %v\n", d.Values())
 	res += fmt.Sprintf("  modified: %v", d.modified)
 
 	return res
 }
-
@@ -88,7 +88,7 @@ func TestContains(t *testing.T) {
 	t.Log(message)
 
 	domain.Expand(1)
-	message = fmt.Sprintf("\nadded 1\n%v\n", domain.values)
+	message = fmt.Sprintf("\nadded 1\n%v\n", domain.Values())
 	t.Log(message)
 
 	check = domain.Contains(1)
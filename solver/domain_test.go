@@ -0,0 +1,48 @@
+package solver_test
+
+import (
+	"testing"
+	"github.com/stretchr/testify/assert"
+	"sudoku-csp/solver"
+)
+
+func TestDomainPositionsFollowSwaps(t *testing.T) {
+	domain := solver.NewDomain(3, 1, 4, 1, 5)
+	assert.Equal(t, 4, domain.Size(), "duplicates are dropped")
+
+	assert.True(t, domain.Remove(1))
+	assert.False(t, domain.Remove(1))
+	assert.False(t, domain.Contains(1))
+	assert.True(t, domain.Contains(5))
+
+	assert.True(t, domain.Assign(4))
+	assert.Equal(t, []int{4}, domain.Values())
+	assert.False(t, domain.Contains(3))
+
+	domain.Expand(3)
+	domain.Expand(-2)
+	domain.Expand(9)
+	assert.ElementsMatch(t, []int{4, 3, -2, 9}, domain.Values())
+
+	for _, value := range []int{4, 3, -2, 9} {
+		assert.True(t, domain.Remove(value), value)
+	}
+	assert.True(t, domain.Empty())
+	assert.False(t, domain.Contains(7))
+}
+
+func TestDomainWideSpanGoesSparse(t *testing.T) {
+	// a dense index over this span would take gigabytes
+	domain := solver.NewDomain(1, 1_000_000_000, -7)
+
+	assert.True(t, domain.Contains(1_000_000_000))
+	assert.False(t, domain.Contains(2))
+
+	assert.True(t, domain.Remove(1))
+	assert.True(t, domain.Assign(-7))
+	assert.Equal(t, []int{-7}, domain.Values())
+
+	domain.Expand(1)
+	assert.ElementsMatch(t, []int{-7, 1}, domain.Values())
+	assert.False(t, domain.Contains(1_000_000_000))
+}
@@ -0,0 +1,184 @@
+package solver
+
+import (
+	"fmt"
+	"strings"
+)
+
+/*
+Explanations
+
+With explanations enabled the Trail keeps a log of why propagation removed or
+assigned each value, undone together with the domains. Why(variable, value)
+walks that log back into a justification chain: "r4c5 can't be 7 because r4c1
+is 7, which is the only place left for 7 in box 3 because...".
+*/
+
+// one statement about a variable: it is assigned Value, or Value is gone from its domain
+type Fact struct {
+	Variable *Variable
+	Value    int
+	Assigned bool
+}
+
+func (f Fact) String() string {
+	if f.Assigned {
+		return fmt.Sprintf("%s = %d", f.Variable.Name, f.Value)
+	}
+
+	return fmt.Sprintf("%s != %d", f.Variable.Name, f.Value)
+}
+
+// why propagation established a fact
+type Reason struct {
+	Fact
+
+	Constraint Constraint // constraint being propagated, nil outside the engine
+	Rule       string     // propagator or checker that made the change
+	Causes     []Fact     // facts it relied on, none means "every value removed before" for assignments
+}
+
+// a fact and the chain of facts that justify it
+type Explanation struct {
+	Fact    Fact
+	Reason  *Reason // nil for decisions, givens and facts that don't hold
+	Because []*Explanation
+	Wipeout bool    // Fact.Variable ran out of values, Because explains each removal
+}
+
+func (e *Explanation) String() string {
+	var builder strings.Builder
+	e.write(&builder, 0)
+
+	return builder.String()
+}
+
+func (e *Explanation) write(builder *strings.Builder, depth int) {
+	builder.WriteString(strings.Repeat("  ", depth))
+
+	if e.Wipeout {
+		fmt.Fprintf(builder, "%s has no values left\n", e.Fact.Variable.Name)
+		for _, cause := range e.Because {
+			cause.write(builder, depth + 1)
+		}
+
+		return
+	}
+
+	builder.WriteString(e.Fact.String())
+
+	switch {
+	case e.Reason != nil && e.Reason.Constraint != nil:
+		fmt.Fprintf(builder, "  [%s on %s]", e.Reason.Rule, e.Reason.Constraint)
+	case e.Reason != nil:
+		fmt.Fprintf(builder, "  [%s]", e.Reason.Rule)
+	case e.Fact.Assigned && e.Fact.Variable.assigned && !e.Fact.Variable.changeable:
+		builder.WriteString("  [given]")
+	case e.Fact.Assigned && e.Fact.Variable.Assignment() == e.Fact.Value:
+		builder.WriteString("  [decision]")
+	default:
+		builder.WriteString("  [unexplained]")
+	}
+	builder.WriteString("\n")
+
+	for _, cause := range e.Because {
+		cause.write(builder, depth + 1)
+	}
+}
+
+
+// Trail side
+
+// start logging reasons, cheap to leave off when nobody asks why
+func (t *Trail) EnableExplanations() {
+	t.explaining = true
+}
+
+func (t *Trail) Explaining() bool {
+	return t.explaining
+}
+
+// explanation of the last domain wipeout, kept after the search undid it
+func (t *Trail) LastWipeout() *Explanation {
+	return t.lastWipeout
+}
+
+func (t *Trail) explain(reason Reason) {
+	if t.explaining {
+		t.reasons = append(t.reasons, reason)
+	}
+}
+
+// every value of variable was removed, explain each removal
+func (t *Trail) explainWipeout(variable *Variable) {
+	if !t.explaining { return }
+
+	t.lastWipeout = &Explanation{
+		Fact:    Fact{Variable: variable},
+		Because: t.explainRemovals(variable, len(t.reasons), map[Fact]bool{}),
+		Wipeout: true,
+	}
+}
+
+// why variable can't take value: a recorded removal, or an assignment to something else
+func (t *Trail) Why(variable *Variable, value int) *Explanation {
+	fact := Fact{Variable: variable, Value: value}
+
+	if variable.assigned && variable.Assignment() != value {
+		assignment := Fact{Variable: variable, Value: variable.Assignment(), Assigned: true}
+
+		explanation := t.explainFact(fact, len(t.reasons), map[Fact]bool{})
+		if explanation.Reason == nil {
+			explanation.Because = []*Explanation{t.explainFact(assignment, len(t.reasons), map[Fact]bool{})}
+		}
+
+		return explanation
+	}
+
+	return t.explainFact(fact, len(t.reasons), map[Fact]bool{})
+}
+
+// explain fact using only reasons logged before index before
+func (t *Trail) explainFact(fact Fact, before int, visited map[Fact]bool) *Explanation {
+	explanation := &Explanation{Fact: fact}
+	if visited[fact] { return explanation }
+	visited[fact] = true
+
+	index := t.findReason(fact, before)
+	if index == -1 { return explanation }
+
+	reason := t.reasons[index]
+	explanation.Reason = &reason
+
+	if len(reason.Causes) == 0 && fact.Assigned {
+		explanation.Because = t.explainRemovals(fact.Variable, index, visited)
+		return explanation
+	}
+
+	for _, cause := range reason.Causes {
+		explanation.Because = append(explanation.Because, t.explainFact(cause, index, visited))
+	}
+
+	return explanation
+}
+
+func (t *Trail) explainRemovals(variable *Variable, before int, visited map[Fact]bool) []*Explanation {
+	removals := []*Explanation{}
+
+	for index := 0; index < before; index++ {
+		reason := t.reasons[index]
+		if reason.Variable != variable || reason.Assigned { continue }
+
+		removals = append(removals, t.explainFact(reason.Fact, index + 1, visited))
+	}
+
+	return removals
+}
+
+func (t *Trail) findReason(fact Fact, before int) int {
+	for index := before - 1; index >= 0; index-- {
+		if t.reasons[index].Fact == fact { return index }
+	}
+
+	return -1
+}
@@ -0,0 +1,81 @@
+package solver
+
+import "fmt"
+
+/*
+Instance features
+
+Cheap measurements of a network that predict which configuration solves it
+fastest (see selection.go): its size, how many variables are given, how the
+domains are spread, and what propagation alone makes of it.
+*/
+type Features struct {
+	Variables   int
+	Constraints int
+	Givens      int   // assigned before propagation
+	MaxDomain   int   // largest domain, the board side for Sudoku
+	DomainSizes []int // unassigned variables by domain size, before propagation
+
+	// after NorvigCheck from the current state, undone again
+	Propagated int     // assigned variables
+	MeanDomain float64 // mean domain size of the variables still unassigned
+	Wipeout    bool    // propagation alone proved the network unsolvable
+}
+
+// share of the variables assigned once propagation is done
+func (f Features) Filled() float64 {
+	if f.Variables == 0 { return 1 }
+
+	return float64(f.Propagated) / float64(f.Variables)
+}
+
+func (f Features) String() string {
+	return fmt.Sprintf(
+		"variables: %d, constraints: %d, givens: %d, max domain: %d, propagated: %d (%.0f%%), mean domain: %.2f, wipeout: %v",
+		f.Variables, f.Constraints, f.Givens, f.MaxDomain, f.Propagated, f.Filled() * 100, f.MeanDomain, f.Wipeout,
+	)
+}
+
+// measure network, leaving it exactly as it was
+func ExtractFeatures(network *Network) Features {
+	features := Features{
+		Variables:   len(network.variables),
+		Constraints: len(network.constraints),
+	}
+
+	for _, variable := range network.variables {
+		features.MaxDomain = max(features.MaxDomain, len(variable.domain.values))
+	}
+
+	features.DomainSizes = make([]int, features.MaxDomain + 1)
+	for _, variable := range network.variables {
+		if variable.assigned {
+			features.Givens++
+		} else {
+			features.DomainSizes[variable.Size()]++
+		}
+	}
+
+	const snapshotName = "solver.ExtractFeatures"
+	network.Snapshot(snapshotName)
+	defer network.DropSnapshot(snapshotName)
+	defer network.Restore(snapshotName)
+
+	features.Wipeout = !NorvigCheck{}.Enforce(network, NewTrail())
+
+	open, total := 0, 0
+	for _, variable := range network.variables {
+		if variable.assigned {
+			features.Propagated++
+		} else {
+			open++
+			total += variable.Size()
+		}
+	}
+
+	if open > 0 {
+		features.MeanDomain = float64(total) / float64(open)
+	}
+
+	return features
+}
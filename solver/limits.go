@@ -0,0 +1,105 @@
+package solver
+
+import (
+	"fmt"
+	"time"
+)
+
+// which budget stopped a search
+type Limit int
+
+const (
+	NoLimit Limit = iota
+	TimeLimit
+	NodeLimit
+	BacktrackLimit
+	TrailLimit
+	SolutionLimit
+	DiscrepancyLimit
+)
+
+func (l Limit) String() string {
+	return [...]string{"none", "time", "nodes", "backtracks", "trail size", "solutions", "discrepancies"}[l]
+}
+
+/*
+Budgets for BacktrackSolver, zero means unlimited
+
+Time caps every Solve on top of the duration passed to it, TrailSize bounds
+the number of trail entries (a rough proxy for memory), Solutions stops
+SolveAll once that many solutions were reported and Discrepancies caps the
+iterations of a discrepancy search (see discrepancy.go).
+*/
+type Limits struct {
+	Time          time.Duration
+	Nodes         int
+	Backtracks    int
+	TrailSize     int
+	Solutions     int
+	Discrepancies int
+}
+
+// Counters of the last Solve / SolveAll
+type Stats struct {
+	Nodes      int // values tried
+	Backtracks int // values undone after failing
+	Solutions  int
+	MaxTrail   int
+	Iterations int // discrepancy search iterations started
+	Restarts   int // runs given up under a RestartPolicy
+	Elapsed    time.Duration
+	Exhausted  Limit // budget that cut the search short, NoLimit when it ran to completion
+}
+
+func (s Stats) String() string {
+	return fmt.Sprintf(
+		"nodes: %d, backtracks: %d, solutions: %d, max trail: %d, iterations: %d, restarts: %d, elapsed: %v, exhausted: %v",
+		s.Nodes, s.Backtracks, s.Solutions, s.MaxTrail, s.Iterations, s.Restarts, s.Elapsed, s.Exhausted,
+	)
+}
+
+// last search was stopped by a limit, so a false result doesn't mean unsolvable
+func (bt *BacktrackSolver) Stopped() bool {
+	return bt.Stats.Exhausted != NoLimit
+}
+
+func (bt *BacktrackSolver) deadline(timeLeft time.Duration) time.Time {
+	if bt.Limits.Time > 0 && bt.Limits.Time < timeLeft {
+		timeLeft = bt.Limits.Time
+	}
+
+	return time.Now().Add(timeLeft)
+}
+
+// records and reports the first budget that ran out
+func (bt *BacktrackSolver) exhausted(deadline time.Time) bool {
+	if bt.Stats.Exhausted != NoLimit { return true }
+
+	if !time.Now().Before(deadline) {
+		bt.Stats.Exhausted = TimeLimit
+		return true
+	}
+
+	return bt.overBudget()
+}
+
+// exhausted without reading the clock
+func (bt *BacktrackSolver) overBudget() bool {
+	if bt.Stats.Exhausted != NoLimit { return true }
+
+	limits, stats := bt.Limits, &bt.Stats
+	stats.MaxTrail = max(stats.MaxTrail, bt.Trail.Size())
+
+	switch {
+	case limits.Nodes > 0 && stats.Nodes >= limits.Nodes:
+		stats.Exhausted = NodeLimit
+	case limits.Backtracks > 0 && stats.Backtracks >= limits.Backtracks:
+		stats.Exhausted = BacktrackLimit
+	case limits.TrailSize > 0 && bt.Trail.Size() > limits.TrailSize:
+		stats.Exhausted = TrailLimit
+	case limits.Solutions > 0 && stats.Solutions >= limits.Solutions:
+		stats.Exhausted = SolutionLimit
+	}
+
+	return stats.Exhausted != NoLimit
+}
@@ -5,9 +5,17 @@ import (
 )
 
 type Network struct {
-	variables   []*Variable
-	constraints []Constraint
-	varToConst  map[*Variable][]Constraint
+	variables       []*Variable
+	constraints     []Constraint
+	softConstraints []*SoftConstraint
+	varToConst      map[*Variable][]Constraint
+	snapshots       map[string]*snapshot
+	propagation     *Propagation
+
+	// built by Finalize, dropped whenever variables or constraints change
+	finalized bool
+	neighbors map[*Variable][]*Variable
+	buckets   *sizeBuckets
 }
 
 func NewNetwork() *Network {
@@ -15,6 +23,7 @@ func NewNetwork() *Network {
 		variables:   []*Variable{},
 		constraints: []Constraint{},
 		varToConst:  map[*Variable][]Constraint{},
+		snapshots:   map[string]*snapshot{},
 	}
 }
 
@@ -23,6 +32,7 @@ func NewNetwork() *Network {
 func (n *Network) AddVariable(variable *Variable) {
 	n.variables = append(n.variables, variable)
 	n.varToConst[variable] = []Constraint{}
+	n.finalized = false
 }
 
 func (n *Network) AddConstraint(constraint Constraint) {
@@ -31,8 +41,62 @@ func (n *Network) AddConstraint(constraint Constraint) {
 	for _, variable := range constraint.Variables() {
 		n.varToConst[variable] = append(n.varToConst[variable], constraint)
 	}
+
+	n.finalized = false
 }
 
+/*
+Precompute neighbor lists and the domain size buckets MRV selects from
+
+Runs lazily on first use after the network changed, calling it once the model is
+built just moves that cost up front. A variable is tracked by the last network
+finalized with it.
+*/
+func (n *Network) Finalize() {
+	if n.finalized { return }
+
+	n.neighbors = make(map[*Variable][]*Variable, len(n.variables))
+	seenBy     := make(map[*Variable]*Variable, len(n.variables))
+
+	for _, variable := range n.variables {
+		neighbors := []*Variable{}
+
+		for _, constraint := range n.varToConst[variable] {
+			for _, other := range constraint.Variables() {
+				if other == variable || seenBy[other] == variable { continue }
+
+				seenBy[other] = variable
+				neighbors = append(neighbors, other)
+			}
+		}
+
+		n.neighbors[variable] = neighbors
+	}
+
+	n.buckets   = newSizeBuckets(n.variables)
+	n.finalized = true
+}
+
+// drop every constraint added after the first count, newest first
+func (n *Network) truncateConstraints(count int) {
+	for len(n.constraints) > count {
+		constraint   := n.constraints[len(n.constraints) - 1]
+		n.constraints = n.constraints[:len(n.constraints) - 1]
+
+		for _, variable := range constraint.Variables() {
+			constraints := n.varToConst[variable]
+
+			for index := len(constraints) - 1; index >= 0; index-- {
+				if constraints[index] == constraint {
+					n.varToConst[variable] = append(constraints[:index], constraints[index + 1:]...)
+					break
+				}
+			}
+		}
+
+		n.finalized = false
+	}
+}
 
 // Accessors
 
@@ -48,23 +112,16 @@ func (n *Network) GetConstraints(variable *Variable) []Constraint {
 	return n.varToConst[variable]
 }
 
+// variables sharing a constraint with variable, owned by the network so don't modify it
 func (n *Network) GetNeighbors(variable *Variable) []*Variable {
-	set := map[*Variable]bool{}
-
-	for _, constraint := range n.varToConst[variable] {
-		for _, other := range constraint.Variables() {
-			if !set[other] && other != variable {
-				set[other] = true
-			}
-		}
-	}
-
-	neighbors := make([]*Variable, 0, len(set))
-	for neighbor := range set {
-		neighbors = append(neighbors, neighbor)
-	}
+	n.Finalize()
+	return n.neighbors[variable]
+}
 
-	return neighbors
+// unassigned variables with the smallest domain, owned by the network so don't modify it
+func (n *Network) SmallestUnassigned() []*Variable {
+	n.Finalize()
+	return n.buckets.smallest()
 }
 
 func (n *Network) IsConsistent() bool {
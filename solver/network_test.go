@@ -0,0 +1,81 @@
+package solver_test
+
+import (
+	"testing"
+	"github.com/stretchr/testify/assert"
+	"sudoku-csp/solver"
+)
+
+func TestGetNeighborsDeduplicated(t *testing.T) {
+	a := solver.NewVariable("a", []int{1, 2, 3}, nil)
+	b := solver.NewVariable("b", []int{1, 2, 3}, nil)
+	c := solver.NewVariable("c", []int{1, 2, 3}, nil)
+
+	network := solver.NewNetwork()
+	network.AddVariable(a)
+	network.AddVariable(b)
+	network.AddVariable(c)
+	network.AddConstraint(solver.NewAllDiffConstraint([]*solver.Variable{a, b}))
+	network.AddConstraint(solver.NewAllDiffConstraint([]*solver.Variable{a, b, c}))
+
+	assert.Equal(t, []*solver.Variable{b, c}, network.GetNeighbors(a))
+	assert.Equal(t, []*solver.Variable{a, b}, network.GetNeighbors(c))
+
+	// adding a constraint after the first lookup refreshes the lists
+	d := solver.NewVariable("d", []int{1, 2, 3}, nil)
+	network.AddVariable(d)
+	network.AddConstraint(solver.NewAllDiffConstraint([]*solver.Variable{c, d}))
+
+	assert.Equal(t, []*solver.Variable{a, b, d}, network.GetNeighbors(c))
+}
+
+func TestMRVFollowsTrail(t *testing.T) {
+	network   := newAllDiffNetwork(4)
+	trail     := solver.NewTrail()
+	variables := network.Variables()
+
+	assert.Equal(t, variables[0], solver.MRV{}.Select(network))
+
+	trail.PlaceMarker()
+	trail.Push(variables[2])
+	variables[2].RemoveValueFromDomain(1)
+	assert.Equal(t, variables[2], solver.MRV{}.Select(network))
+	assert.Len(t, network.SmallestUnassigned(), 1)
+
+	trail.Push(variables[2])
+	variables[2].AssignValue(2)
+	assert.Equal(t, variables[0], solver.MRV{}.Select(network))
+
+	trail.Undo()
+	assert.Equal(t, variables[0], solver.MRV{}.Select(network))
+	assert.Len(t, network.SmallestUnassigned(), 4)
+
+	for _, variable := range variables {
+		variable.AssignValue(variable.Values()[0])
+	}
+	assert.Nil(t, solver.MRV{}.Select(network))
+	assert.Nil(t, solver.MRVWithDegree{}.Select(network))
+}
+
+func TestMRVAcrossSummaryWords(t *testing.T) {
+	// enough variables for the bucket bitsets to need a summary level
+	network   := newAllDiffNetwork(300)
+	variables := network.Variables()
+	trail     := solver.NewTrail()
+
+	trail.PlaceMarker()
+	for _, index := range []int{299, 130, 64} {
+		trail.Push(variables[index])
+		variables[index].RemoveValueFromDomain(1)
+	}
+	assert.Equal(t, variables[64], solver.MRV{}.Select(network))
+	assert.Equal(t, []*solver.Variable{variables[64], variables[130], variables[299]}, network.SmallestUnassigned())
+
+	trail.Push(variables[64])
+	variables[64].AssignValue(2)
+	assert.Equal(t, variables[130], solver.MRV{}.Select(network))
+
+	trail.Undo()
+	assert.Equal(t, variables[0], solver.MRV{}.Select(network))
+	assert.Len(t, network.SmallestUnassigned(), 300)
+}
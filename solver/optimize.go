@@ -0,0 +1,212 @@
+package solver
+
+import (
+	"math"
+	"time"
+)
+
+type Sense int
+
+const (
+	Minimize Sense = iota
+	Maximize
+)
+
+// whether value beats incumbent in this direction
+func (s Sense) Better(value, incumbent int) bool {
+	if s == Maximize {
+		return value > incumbent
+	}
+
+	return value < incumbent
+}
+
+// What an optimizing search scores assignments with
+type Objective interface {
+	// score of a complete assignment
+	Evaluate(network *Network) int
+
+	// best score any completion of the current partial assignment could reach:
+	// an upper bound when maximizing, a lower bound when minimizing
+	Bound(network *Network, sense Sense) int
+}
+
+
+// sum of Coefficient * value over the terms
+type LinearObjective struct {
+	Terms []Term
+}
+
+type Term struct {
+	Variable    *Variable
+	Coefficient int
+}
+
+// every variable with the same coefficient
+func SumObjective(variables []*Variable, coefficient int) LinearObjective {
+	terms := make([]Term, len(variables))
+	for index, variable := range variables {
+		terms[index] = Term{variable, coefficient}
+	}
+
+	return LinearObjective{Terms: terms}
+}
+
+func (o LinearObjective) Evaluate(network *Network) int {
+	total := 0
+	for _, term := range o.Terms {
+		total += term.Coefficient * term.Variable.Assignment()
+	}
+
+	return total
+}
+
+// each term at its best over the values left in its domain
+func (o LinearObjective) Bound(network *Network, sense Sense) int {
+	total := 0
+
+	for _, term := range o.Terms {
+		best, found := 0, false
+
+		for _, value := range term.Variable.Values() {
+			contribution := term.Coefficient * value
+			if !found || sense.Better(contribution, best) {
+				best, found = contribution, true
+			}
+		}
+
+		total += best
+	}
+
+	return total
+}
+
+
+// user function objective, without BoundFunc nothing gets pruned
+type FuncObjective struct {
+	EvaluateFunc func(*Network) int
+	BoundFunc    func(*Network, Sense) int
+}
+
+func (o FuncObjective) Evaluate(network *Network) int {
+	return o.EvaluateFunc(network)
+}
+
+func (o FuncObjective) Bound(network *Network, sense Sense) int {
+	if o.BoundFunc != nil {
+		return o.BoundFunc(network, sense)
+	}
+
+	if sense == Maximize {
+		return math.MaxInt
+	}
+
+	return math.MinInt
+}
+
+
+/*
+Branch-and-bound on top of a BacktrackSolver's strategies
+
+Explores the whole search space, pruning branches whose objective bound can't
+beat the best solution found so far. Every improving solution is passed to
+OnImprove as it is found.
+*/
+type OptimizingSolver struct {
+	*BacktrackSolver
+
+	Objective Objective
+	Sense     Sense
+	OnImprove func(value int, solution []int)
+
+	Best      []int // assignment per network.Variables() index
+	BestValue int
+	Optimal   bool  // search space exhausted, Best can't be beaten
+}
+
+func NewOptimizingSolver(bt *BacktrackSolver, objective Objective, sense Sense) *OptimizingSolver {
+	return &OptimizingSolver{
+		BacktrackSolver: bt,
+		Objective:       objective,
+		Sense:           sense,
+	}
+}
+
+/*
+Search for the best solution within timeLeft, true if any solution was found.
+
+Afterwards the best solution is assigned in the network (above a new trail
+marker, so a Trail.Undo takes it back off).
+*/
+func (o *OptimizingSolver) Optimize(timeLeft time.Duration) bool {
+	o.Best, o.Optimal = nil, false
+	o.HasSolution = false
+
+	o.Optimal = o.branch(time.Now().Add(timeLeft))
+
+	if o.Best != nil {
+		o.HasSolution = true
+		o.applyBest()
+	}
+
+	return o.HasSolution
+}
+
+// false when the deadline cut the search short
+func (o *OptimizingSolver) branch(deadline time.Time) bool {
+	if time.Now().After(deadline) { return false }
+
+	if o.Best != nil && !o.Sense.Better(o.Objective.Bound(o.Network, o.Sense), o.BestValue) {
+		return true
+	}
+
+	variable := o.Select(o.Network)
+	if variable == nil {
+		o.record()
+		return true
+	}
+
+	for _, value := range o.OrderValues(variable, o.Network) {
+		o.Trail.PlaceMarker()
+		o.Trail.Push(variable)
+		variable.AssignValue(value)
+
+		completed := true
+		if o.Enforce(o.Network, o.Trail) {
+			completed = o.branch(deadline)
+		}
+
+		o.Trail.Undo()
+
+		if !completed { return false }
+	}
+
+	return true
+}
+
+func (o *OptimizingSolver) record() {
+	value := o.Objective.Evaluate(o.Network)
+	if o.Best != nil && !o.Sense.Better(value, o.BestValue) { return }
+
+	solution := make([]int, len(o.Network.variables))
+	for index, variable := range o.Network.variables {
+		solution[index] = variable.Assignment()
+	}
+
+	o.Best, o.BestValue = solution, value
+
+	if o.OnImprove != nil {
+		o.OnImprove(value, solution)
+	}
+}
+
+func (o *OptimizingSolver) applyBest() {
+	o.Trail.PlaceMarker()
+
+	for index, variable := range o.Network.variables {
+		if variable.assigned && variable.Assignment() == o.Best[index] { continue }
+
+		o.Trail.Push(variable)
+		variable.AssignValue(o.Best[index])
+	}
+}
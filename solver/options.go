@@ -0,0 +1,128 @@
+package solver
+
+/*
+Functional Options
+
+New builds a BacktrackSolver from a network and whatever options are given,
+the rest left at the defaults: MRV, DefaultValOrder, ForwardChecking, a fresh
+Trail, no limits, no tracer and no restarts. Value orders are only randomized
+when asked for, with WithShuffledValues.
+
+	bt := solver.New(network, solver.WithChecker(solver.AllDiffGAC{}), solver.WithShuffledValues(), solver.WithSeed(7))
+*/
+type Option func(*options)
+
+type options struct {
+	trail       *Trail
+	varSelector VarSelector
+	valSelector ValSelector
+	checker     ConsistencyChecker
+	limits      Limits
+	mode        SearchMode
+	tracer      Tracer
+	restarts    RestartPolicy
+	seed        uint64
+	seeded      bool
+	shuffled    bool
+}
+
+func New(network *Network, opts ...Option) *BacktrackSolver {
+	o := options{
+		varSelector: MRV{},
+		valSelector: DefaultValOrder{},
+		checker:     ForwardChecking{},
+	}
+
+	for _, opt := range opts {
+		opt(&o)
+	}
+
+	if o.trail == nil {
+		o.trail = NewTrail()
+	}
+
+	if !o.seeded {
+		o.seed = RandomSeed()
+	}
+
+	switch order := o.valSelector.(type) {
+	case *RandomValOrder:
+		if o.seeded {
+			o.valSelector = NewRandomValOrder(o.seed, order.Inner)
+		} else {
+			o.seed = order.Seed
+		}
+	default:
+		if o.shuffled {
+			o.valSelector = NewRandomValOrder(o.seed, order)
+		}
+	}
+
+	return &BacktrackSolver{
+		Network:            network,
+		Trail:              o.trail,
+		Limits:             o.limits,
+		Mode:               o.mode,
+		Tracer:             o.tracer,
+		Restarts:           o.restarts,
+		Seed:               o.seed,
+		VarSelector:        o.varSelector,
+		ValSelector:        o.valSelector,
+		ConsistencyChecker: o.checker,
+	}
+}
+
+// Options
+
+// share a trail with other code working on the same network
+func WithTrail(trail *Trail) Option {
+	return func(o *options) { o.trail = trail }
+}
+
+func WithVarSelector(selector VarSelector) Option {
+	return func(o *options) { o.varSelector = selector }
+}
+
+func WithValSelector(selector ValSelector) Option {
+	return func(o *options) { o.valSelector = selector }
+}
+
+func WithChecker(checker ConsistencyChecker) Option {
+	return func(o *options) { o.checker = checker }
+}
+
+// selectors and checker of a registered or trained configuration, see selection.go
+func WithConfiguration(configuration Configuration) Option {
+	return func(o *options) {
+		o.varSelector = configuration.VarSelector
+		o.valSelector = configuration.ValSelector
+		o.checker     = configuration.ConsistencyChecker
+	}
+}
+
+func WithLimits(limits Limits) Option {
+	return func(o *options) { o.limits = limits }
+}
+
+func WithMode(mode SearchMode) Option {
+	return func(o *options) { o.mode = mode }
+}
+
+func WithTracer(tracer Tracer) Option {
+	return func(o *options) { o.tracer = tracer }
+}
+
+// seed for the solver's randomness, a RandomValOrder value selector included, never changes which order is used
+func WithSeed(seed uint64) Option {
+	return func(o *options) { o.seed, o.seeded = seed, true }
+}
+
+// shuffle the chosen value order, see RandomValOrder, seeded by WithSeed or at random
+func WithShuffledValues() Option {
+	return func(o *options) { o.shuffled = true }
+}
+
+// Solve starts over under policy, pair it with a randomized value order (WithShuffledValues) or it retraces the same tree
+func WithRestarts(policy RestartPolicy) Option {
+	return func(o *options) { o.restarts = policy }
+}
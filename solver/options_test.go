@@ -0,0 +1,134 @@
+package solver_test
+
+import (
+	"bytes"
+	"testing"
+	"time"
+	"github.com/stretchr/testify/assert"
+	"sudoku-csp/solver"
+)
+
+func TestNewDefaults(t *testing.T) {
+	bt := solver.New(newAllDiffNetwork(3))
+
+	assert.NotNil(t, bt.Trail)
+	assert.Equal(t, solver.MRV{}, bt.VarSelector)
+	assert.Equal(t, solver.DefaultValOrder{}, bt.ValSelector)
+	assert.Equal(t, solver.ForwardChecking{}, bt.ConsistencyChecker)
+	assert.Nil(t, bt.Tracer)
+	assert.Nil(t, bt.Restarts)
+	assert.True(t, bt.Solve(time.Second))
+}
+
+func TestNewOptions(t *testing.T) {
+	trail  := solver.NewTrail()
+	limits := solver.Limits{Nodes: 100}
+
+	bt := solver.New(
+		newAllDiffNetwork(3),
+		solver.WithTrail(trail),
+		solver.WithVarSelector(solver.FirstUnassigned{}),
+		solver.WithValSelector(solver.LeastConstrainingValue{}),
+		solver.WithChecker(solver.AllDiffGAC{}),
+		solver.WithLimits(limits),
+		solver.WithMode(solver.LimitedDiscrepancy),
+		solver.WithShuffledValues(),
+		solver.WithSeed(3),
+	)
+
+	assert.Same(t, trail, bt.Trail)
+	assert.Equal(t, solver.FirstUnassigned{}, bt.VarSelector)
+	assert.Equal(t, solver.NewRandomValOrder(3, solver.LeastConstrainingValue{}), bt.ValSelector)
+	assert.Equal(t, solver.AllDiffGAC{}, bt.ConsistencyChecker)
+	assert.Equal(t, limits, bt.Limits)
+	assert.Equal(t, solver.LimitedDiscrepancy, bt.Mode)
+
+	// a seed or a restart policy alone keeps the chosen order
+	bt = solver.New(
+		newAllDiffNetwork(3),
+		solver.WithValSelector(solver.LeastConstrainingValue{}),
+		solver.WithRestarts(solver.LubyRestarts{}),
+		solver.WithSeed(3),
+	)
+	assert.Equal(t, solver.LeastConstrainingValue{}, bt.ValSelector)
+
+	// but the seed reaches a randomized order given directly
+	bt = solver.New(
+		newAllDiffNetwork(3),
+		solver.WithValSelector(solver.NewRandomValOrder(1, solver.LeastConstrainingValue{})),
+		solver.WithSeed(3),
+	)
+	assert.Equal(t, solver.NewRandomValOrder(3, solver.LeastConstrainingValue{}), bt.ValSelector)
+}
+
+// counts events, checked against the search's own stats
+type countingTracer struct {
+	decisions, wipeouts, backtracks, solutions int
+}
+
+func (c *countingTracer) Decide(_ *solver.Variable, _, _ int, ok bool) {
+	c.decisions++
+	if !ok {
+		c.wipeouts++
+	}
+}
+
+func (c *countingTracer) Backtrack(*solver.Variable, int, int) { c.backtracks++ }
+func (c *countingTracer) Solution(int)                         { c.solutions++ }
+func (c *countingTracer) Restart(int)                          {}
+
+func TestTracerSeesEveryDecision(t *testing.T) {
+	for _, mode := range []solver.SearchMode{solver.DepthFirst, solver.DepthBoundedDiscrepancy} {
+		tracer := &countingTracer{}
+		bt     := solver.New(newAllDiffNetwork(4), solver.WithTracer(tracer), solver.WithMode(mode))
+
+		assert.Equal(t, 24, bt.SolveAll(time.Second, nil))
+		assert.Equal(t, bt.Stats.Nodes, tracer.decisions, "mode %v", mode)
+		assert.Equal(t, bt.Stats.Backtracks, tracer.wipeouts + tracer.backtracks, "mode %v", mode)
+		assert.Equal(t, 24, tracer.solutions, "mode %v", mode)
+	}
+}
+
+func TestSeedReplaysShuffledRun(t *testing.T) {
+	trace := func(options ...solver.Option) (uint64, string) {
+		var out bytes.Buffer
+		options = append(options, solver.WithShuffledValues(), solver.WithTracer(solver.LogTracer{Out: &out}))
+
+		bt := solver.New(newAllDiffNetwork(6), options...)
+		bt.SolveAll(time.Second, nil)
+
+		return bt.Seed, out.String()
+	}
+
+	seed, first := trace()
+	_, replayed := trace(solver.WithSeed(seed))
+	assert.Equal(t, first, replayed)
+
+	assert.Equal(t, uint64(11), solver.New(newAllDiffNetwork(2), solver.WithSeed(11)).Seed)
+
+	// an order seeded by the caller reports its own seed
+	order := solver.NewRandomValOrder(13, nil)
+	assert.Equal(t, uint64(13), solver.New(newAllDiffNetwork(2), solver.WithValSelector(order)).Seed)
+}
+
+func TestLogTracer(t *testing.T) {
+	var out bytes.Buffer
+	bt := solver.New(newAllDiffNetwork(2), solver.WithTracer(solver.LogTracer{Out: &out}))
+
+	assert.True(t, bt.Solve(time.Second))
+	assert.Equal(t, " = 1 (ok)\n   = 2 (ok)\n    solution\n", out.String())
+}
+
+func TestRestartPolicies(t *testing.T) {
+	luby := make([]int, 15)
+	for run := range luby {
+		luby[run] = solver.LubyRestarts{Scale: 1}.Backtracks(run)
+	}
+	assert.Equal(t, []int{1, 1, 2, 1, 1, 2, 4, 1, 1, 2, 1, 1, 2, 4, 8}, luby)
+	assert.Equal(t, 400, solver.LubyRestarts{}.Backtracks(6))
+
+	geometric := solver.GeometricRestarts{First: 10, Factor: 2}
+	assert.Equal(t, 10, geometric.Backtracks(0))
+	assert.Equal(t, 80, geometric.Backtracks(3))
+	assert.Equal(t, 0, geometric.Backtracks(100), "a budget past int32 means no limit")
+}
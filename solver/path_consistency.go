@@ -0,0 +1,213 @@
+package solver
+
+import "slices"
+
+/*
+Path consistency (PC-2) over the binary constraint graph of a Network
+
+Every pair of variables gets a binary relation over their current domain values:
+a != b when some AllDiff holds both of them, every pair otherwise. A pair (a, b)
+survives for variables i, j only if, for every third variable k, some value c of
+k is allowed with both a and b. Other constraint types have no binary expansion
+and are left out.
+
+Preprocessing for research comparisons: the network itself is not modified,
+the tightened relations are returned instead. Costs O(n^3 d^3) in the worst case.
+*/
+type PathConsistency struct {
+	variables []*Variable
+	index     map[*Variable]int
+	values    [][]int
+	relations [][]bitRows // relations[i][j][a] = bits of j's values allowed with i's a-th value
+
+	Eliminated int  // pairs removed from the initial relations
+	Revisions  int  // path revisions performed
+	Consistent bool // false when some relation lost all of its pairs
+}
+
+type bitRows [][]uint64
+
+func newBitRows(rows, cols int) bitRows {
+	words  := (cols + 63) / 64
+	matrix := make(bitRows, rows)
+
+	for row := range matrix {
+		matrix[row] = make([]uint64, words)
+	}
+
+	return matrix
+}
+
+func (m bitRows) has(row, col int) bool { return m[row][col / 64] & (1 << (col % 64)) != 0 }
+func (m bitRows) set(row, col int)      { m[row][col / 64] |= 1 << (col % 64) }
+func (m bitRows) unset(row, col int)    { m[row][col / 64] &^= 1 << (col % 64) }
+
+func (m bitRows) empty() bool {
+	for _, row := range m {
+		for _, word := range row {
+			if word != 0 { return false }
+		}
+	}
+
+	return true
+}
+
+func intersects(left, right []uint64) bool {
+	for index := range left {
+		if left[index] & right[index] != 0 { return true }
+	}
+
+	return false
+}
+
+// build the binary relations for network and run PC-2 over them
+func EnforcePathConsistency(network *Network) *PathConsistency {
+	pc := newPathConsistency(network)
+	pc.run()
+
+	return pc
+}
+
+func newPathConsistency(network *Network) *PathConsistency {
+	n  := len(network.variables)
+	pc := &PathConsistency{
+		variables:  network.variables,
+		index:      make(map[*Variable]int, n),
+		values:     make([][]int, n),
+		relations:  make([][]bitRows, n),
+		Consistent: true,
+	}
+
+	for i, variable := range network.variables {
+		pc.index[variable] = i
+		pc.values[i] = slices.Sorted(slices.Values(variable.Values()))
+	}
+
+	different := make([]map[int]bool, n)
+	for i := range different {
+		different[i] = map[int]bool{}
+	}
+	for _, constraint := range network.constraints {
+		if _, ok := constraint.(*AllDiffConstraint); !ok { continue }
+
+		for _, left := range constraint.Variables() {
+			for _, right := range constraint.Variables() {
+				if left != right {
+					different[pc.index[left]][pc.index[right]] = true
+				}
+			}
+		}
+	}
+
+	for i := range n {
+		pc.relations[i] = make([]bitRows, n)
+
+		for j := range n {
+			if i == j { continue }
+
+			relation := newBitRows(len(pc.values[i]), len(pc.values[j]))
+			for a, left := range pc.values[i] {
+				for b, right := range pc.values[j] {
+					if !different[i][j] || left != right {
+						relation.set(a, b)
+					}
+				}
+			}
+
+			pc.relations[i][j] = relation
+		}
+	}
+
+	return pc
+}
+
+// (i, k, j): tighten the relation between i and j through k
+type pathTriple struct{ i, k, j int }
+
+func (pc *PathConsistency) run() {
+	n     := len(pc.variables)
+	queue := []pathTriple{}
+
+	for i := range n {
+		for j := i + 1; j < n; j++ {
+			for k := range n {
+				if k != i && k != j {
+					queue = append(queue, pathTriple{i, k, j})
+				}
+			}
+		}
+	}
+
+	for len(queue) > 0 {
+		path := queue[0]
+		queue = queue[1:]
+
+		if !pc.revise(path.i, path.k, path.j) { continue }
+
+		if pc.relations[path.i][path.j].empty() {
+			pc.Consistent = false
+			return
+		}
+
+		// relations that went through the tightened pair need another look
+		for m := range n {
+			if m == path.i || m == path.j { continue }
+
+			queue = append(queue, pathTriple{path.i, path.j, m}, pathTriple{m, path.i, path.j})
+		}
+	}
+}
+
+func (pc *PathConsistency) revise(i, k, j int) bool {
+	pc.Revisions++
+
+	ij, ji := pc.relations[i][j], pc.relations[j][i]
+	ik, jk := pc.relations[i][k], pc.relations[j][k]
+	changed := false
+
+	for a := range pc.values[i] {
+		for b := range pc.values[j] {
+			if !ij.has(a, b) || intersects(ik[a], jk[b]) { continue }
+
+			ij.unset(a, b)
+			ji.unset(b, a)
+			pc.Eliminated++
+			changed = true
+		}
+	}
+
+	return changed
+}
+
+// Accessors
+
+// whether x = a and y = b are still allowed together
+func (pc *PathConsistency) Allows(x *Variable, a int, y *Variable, b int) bool {
+	i, okX := pc.index[x]
+	j, okY := pc.index[y]
+	if !okX || !okY || i == j { return false }
+
+	row := slices.Index(pc.values[i], a)
+	col := slices.Index(pc.values[j], b)
+	if row == -1 || col == -1 { return false }
+
+	return pc.relations[i][j].has(row, col)
+}
+
+// allowed (x value, y value) pairs of the tightened relation
+func (pc *PathConsistency) Relation(x, y *Variable) [][2]int {
+	i, okX := pc.index[x]
+	j, okY := pc.index[y]
+	if !okX || !okY || i == j { return nil }
+
+	pairs := [][2]int{}
+	for a, left := range pc.values[i] {
+		for b, right := range pc.values[j] {
+			if pc.relations[i][j].has(a, b) {
+				pairs = append(pairs, [2]int{left, right})
+			}
+		}
+	}
+
+	return pairs
+}
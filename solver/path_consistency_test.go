@@ -0,0 +1,41 @@
+package solver_test
+
+import (
+	"testing"
+	"github.com/stretchr/testify/assert"
+	"sudoku-csp/solver"
+)
+
+func TestPathConsistencyTwoColorTriangle(t *testing.T) {
+	network := newAllDiffNetwork(3)
+	for _, variable := range network.Variables() {
+		variable.RemoveValueFromDomain(3)
+	}
+
+	pc := solver.EnforcePathConsistency(network)
+	assert.False(t, pc.Consistent)
+	assert.Greater(t, pc.Eliminated, 0)
+}
+
+func TestPathConsistencyTightensThroughThirdVariable(t *testing.T) {
+	x := solver.NewVariable("x", []int{1, 2}, nil)
+	y := solver.NewVariable("y", []int{1, 2, 3}, nil)
+	z := solver.NewVariable("z", []int{1, 2}, nil)
+
+	network := solver.NewNetwork()
+	for _, variable := range []*solver.Variable{x, y, z} {
+		network.AddVariable(variable)
+	}
+	network.AddConstraint(solver.NewAllDiffConstraint([]*solver.Variable{x, y}))
+	network.AddConstraint(solver.NewAllDiffConstraint([]*solver.Variable{y, z}))
+	network.AddConstraint(solver.NewAllDiffConstraint([]*solver.Variable{x, z}))
+
+	pc := solver.EnforcePathConsistency(network)
+	assert.True(t, pc.Consistent)
+
+	// x and z use up 1 and 2 between them, so y can only be 3
+	assert.ElementsMatch(t, [][2]int{{1, 3}, {2, 3}}, pc.Relation(x, y))
+	assert.False(t, pc.Allows(y, 1, z, 2))
+	assert.True(t, pc.Allows(z, 2, y, 3))
+	assert.Equal(t, 4, pc.Eliminated)
+}
@@ -0,0 +1,135 @@
+package solver
+
+import (
+	"fmt"
+	"time"
+)
+
+// Counters for one stage of a Pipeline, summed over every Enforce call
+type StageStats struct {
+	Name     string
+	Runs     int
+	Failures int
+	Changes  int // trail entries pushed, i.e. domain changes made by the stage
+	Time     time.Duration
+}
+
+/*
+Chains consistency checkers and loops over them until a whole round goes by
+without any domain change (or one of them fails)
+
+Each stage only gets the variables changed since it last ran (by the search or
+by the other stages) marked as modified, so engine based stages keep scheduling
+just the constraints that need another look.
+*/
+type Pipeline struct {
+	Stages []ConsistencyChecker
+	Stats  []StageStats
+	Rounds int
+
+	pending [][]*Variable
+}
+
+func NewPipeline(stages ...ConsistencyChecker) *Pipeline {
+	pipeline := &Pipeline{
+		Stages: stages,
+	}
+	pipeline.ResetStats()
+
+	return pipeline
+}
+
+func (p *Pipeline) ResetStats() {
+	p.Stats  = make([]StageStats, len(p.Stages))
+	p.Rounds = 0
+
+	for index, stage := range p.Stages {
+		p.Stats[index].Name = fmt.Sprintf("%T", stage)
+	}
+}
+
+func (p *Pipeline) Enforce(network *Network, trail *Trail) bool {
+	if len(p.Stats) != len(p.Stages) {
+		p.ResetStats()
+	}
+
+	// every stage starts out owing a look at whatever the search modified
+	dirty := []*Variable{}
+	for _, variable := range network.variables {
+		if variable.modified {
+			dirty = append(dirty, variable)
+			variable.modified = false
+		}
+	}
+
+	p.pending = make([][]*Variable, len(p.Stages))
+	for index := range p.Stages {
+		p.pending[index] = append([]*Variable{}, dirty...)
+	}
+
+	for {
+		p.Rounds++
+		changed := false
+
+		for index, stage := range p.Stages {
+			marked := p.pending[index]
+			p.pending[index] = nil
+			for _, variable := range marked {
+				variable.modified = true
+			}
+
+			before := trail.Size()
+			start  := time.Now()
+			ok     := stage.Enforce(network, trail)
+
+			stats := &p.Stats[index]
+			stats.Runs++
+			stats.Time += time.Since(start)
+
+			for _, variable := range marked {
+				variable.modified = false
+			}
+
+			if !ok {
+				stats.Failures++
+				return false
+			}
+
+			if trail.Size() <= before { continue }
+
+			stats.Changes += trail.Size() - before
+			changed = true
+
+			for _, entry := range trail.stack[before:] {
+				for other := range p.Stages {
+					if other != index {
+						p.pending[other] = append(p.pending[other], entry.variable)
+					}
+				}
+			}
+		}
+
+		if !changed { return true }
+	}
+}
+
+
+// Propagators as standalone checkers, mostly for use as pipeline stages
+
+var (
+	hiddenSingles = PropagationEngine{Propagators: []Propagator{HiddenSingles{}}}
+	nakedSingles  = PropagationEngine{Propagators: []Propagator{NakedSingles{}}}
+	allDiffGAC    = PropagationEngine{Propagators: []Propagator{AllDiffGAC{}}}
+)
+
+func (HiddenSingles) Enforce(network *Network, trail *Trail) bool {
+	return hiddenSingles.Enforce(network, trail)
+}
+
+func (NakedSingles) Enforce(network *Network, trail *Trail) bool {
+	return nakedSingles.Enforce(network, trail)
+}
+
+func (AllDiffGAC) Enforce(network *Network, trail *Trail) bool {
+	return allDiffGAC.Enforce(network, trail)
+}
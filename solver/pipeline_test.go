@@ -0,0 +1,56 @@
+package solver_test
+
+import (
+	"testing"
+	"github.com/stretchr/testify/assert"
+	"sudoku-csp/solver"
+)
+
+func TestAllDiffGACPrunesHallSet(t *testing.T) {
+	network := solver.NewNetwork()
+	x := solver.NewVariable("x", []int{1, 2}, nil)
+	y := solver.NewVariable("y", []int{1, 2}, nil)
+	z := solver.NewVariable("z", []int{1, 2, 3}, nil)
+
+	for _, variable := range []*solver.Variable{x, y, z} {
+		network.AddVariable(variable)
+	}
+	network.AddConstraint(solver.NewAllDiffConstraint([]*solver.Variable{x, y, z}))
+
+	engine := solver.PropagationEngine{Propagators: []solver.Propagator{solver.AllDiffGAC{}}, SeedAll: true}
+	assert.True(t, engine.Enforce(network, solver.NewTrail()))
+
+	assert.Equal(t, []int{3}, z.Values())
+	assert.ElementsMatch(t, []int{1, 2}, x.Values())
+
+	// three variables over two values can't be matched
+	w := solver.NewVariable("w", []int{1, 2}, nil)
+	network.AddVariable(w)
+	network.AddConstraint(solver.NewAllDiffConstraint([]*solver.Variable{x, y, w}))
+	assert.False(t, engine.Enforce(network, solver.NewTrail()))
+}
+
+func TestPipelineRunsToFixpoint(t *testing.T) {
+	network := newAllDiffNetwork(4)
+	trail   := solver.NewTrail()
+
+	pipeline := solver.NewPipeline(solver.ForwardChecking{}, solver.HiddenSingles{}, solver.AllDiffGAC{})
+
+	first := network.Variables()[0]
+	trail.PlaceMarker()
+	trail.Push(first)
+	first.AssignValue(4)
+
+	assert.True(t, pipeline.Enforce(network, trail))
+	assert.Len(t, pipeline.Stats, 3)
+	assert.Equal(t, "solver.ForwardChecking", pipeline.Stats[0].Name)
+	assert.Greater(t, pipeline.Stats[0].Changes, 0)
+
+	for _, stats := range pipeline.Stats {
+		assert.GreaterOrEqual(t, stats.Runs, 2, stats.Name)
+	}
+
+	for _, variable := range network.Variables()[1:] {
+		assert.NotContains(t, variable.Values(), 4)
+	}
+}
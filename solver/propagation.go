@@ -0,0 +1,345 @@
+package solver
+
+import "fmt"
+
+/*
+Propagation engine
+
+Checkers are built from Propagators: rules applied to one constraint at a time.
+The engine keeps a queue of (constraint, propagator) tasks and whenever a task
+prunes a variable, every constraint on that variable is queued again for every
+propagator, until nothing changes (fixpoint) or some domain is wiped out.
+*/
+
+// Order queued tasks run in, cheap rules settle before expensive ones look at the domains
+type Priority int
+
+const (
+	PriorityCheap Priority = iota
+	PriorityLinear
+	PriorityExpensive
+
+	numPriorities
+)
+
+// Result of running a propagator on one constraint
+type Outcome int
+
+const (
+	Consistent Outcome = iota // nothing wrong, domains may have been pruned
+	Entailed                  // satisfied whatever happens next, skip it for the rest of the fixpoint
+	Wipeout                   // a domain emptied or the constraint is violated
+)
+
+// Propagation rule applied to each scheduled constraint
+type Propagator interface {
+	Priority() Priority
+
+	// true when a second run right after the first can't prune anything more,
+	// so the engine doesn't requeue a task because of its own changes
+	Idempotent() bool
+
+	Propagate(constraint Constraint, p *Propagation) Outcome
+}
+
+// Constraints that know how to prune their own variables, used by ConstraintPropagation
+type Propagating interface {
+	Propagate(p *Propagation) Outcome
+}
+
+type propagationTask struct {
+	constraint Constraint
+	propagator int
+}
+
+// State of one fixpoint run, handed to propagators so their pruning gets trailed and scheduled
+type Propagation struct {
+	network     *Network
+	trail       *Trail
+	propagators []Propagator
+
+	queues   [numPriorities][]propagationTask
+	queued   map[propagationTask]bool
+	entailed map[Constraint]bool
+	running  propagationTask
+	busy     bool
+
+	Runs     int
+	Prunings int
+}
+
+func newPropagation() *Propagation {
+	return &Propagation{
+		queued:   map[propagationTask]bool{},
+		entailed: map[Constraint]bool{},
+	}
+}
+
+// Accessors
+
+func (p *Propagation) Network() *Network {
+	return p.network
+}
+
+func (p *Propagation) Trail() *Trail {
+	return p.trail
+}
+
+// Mutators
+
+// remove value from the variable's domain, false on wipeout
+func (p *Propagation) Remove(variable *Variable, value int) bool {
+	return p.RemoveBecause(variable, value)
+}
+
+// Remove, logging the facts that justify it when the trail keeps explanations
+func (p *Propagation) RemoveBecause(variable *Variable, value int, causes ...Fact) bool {
+	if !variable.domain.Contains(value) { return true }
+
+	p.trail.Push(variable)
+	variable.domain.Remove(value)
+	variable.resized()
+	p.Prunings++
+	p.explain(Fact{variable, value, false}, causes)
+
+	if variable.domain.Empty() {
+		p.trail.explainWipeout(variable)
+		return false
+	}
+
+	p.schedule(variable)
+	return true
+}
+
+// assign value to the variable, false when the value isn't in its domain
+func (p *Propagation) Assign(variable *Variable, value int) bool {
+	return p.AssignBecause(variable, value)
+}
+
+// Assign, logging the facts that justify it when the trail keeps explanations
+func (p *Propagation) AssignBecause(variable *Variable, value int, causes ...Fact) bool {
+	if !variable.domain.Contains(value) { return false }
+	if variable.assigned { return true }
+
+	p.trail.Push(variable)
+	variable.AssignValue(value)
+	variable.modified = false
+	p.Prunings++
+	p.explain(Fact{variable, value, true}, causes)
+
+	p.schedule(variable)
+	return true
+}
+
+// Internal Helpers
+
+func (p *Propagation) explain(fact Fact, causes []Fact) {
+	if !p.trail.explaining { return }
+
+	reason := Reason{Fact: fact, Constraint: p.running.constraint}
+	if p.running.constraint != nil {
+		reason.Rule = fmt.Sprintf("%T", p.propagators[p.running.propagator])
+	}
+	reason.Causes = append(reason.Causes, causes...)
+
+	p.trail.explain(reason)
+}
+
+func (p *Propagation) reset(network *Network, trail *Trail, propagators []Propagator) {
+	p.network, p.trail, p.propagators = network, trail, propagators
+
+	for priority := range p.queues {
+		p.queues[priority] = p.queues[priority][:0]
+	}
+	clear(p.queued)
+	clear(p.entailed)
+
+	p.running  = propagationTask{}
+	p.Runs     = 0
+	p.Prunings = 0
+}
+
+func (p *Propagation) enqueue(constraint Constraint) {
+	if p.entailed[constraint] { return }
+
+	for index, propagator := range p.propagators {
+		task := propagationTask{constraint, index}
+
+		if p.queued[task] { continue }
+		if task == p.running && propagator.Idempotent() { continue }
+
+		priority := propagator.Priority()
+		p.queues[priority] = append(p.queues[priority], task)
+		p.queued[task] = true
+	}
+}
+
+func (p *Propagation) schedule(variable *Variable) {
+	for _, constraint := range p.network.varToConst[variable] {
+		p.enqueue(constraint)
+	}
+}
+
+func (p *Propagation) next() (propagationTask, bool) {
+	for priority := range p.queues {
+		queue := p.queues[priority]
+		if len(queue) == 0 { continue }
+
+		task := queue[0]
+		p.queues[priority] = queue[1:]
+		delete(p.queued, task)
+
+		return task, true
+	}
+
+	return propagationTask{}, false
+}
+
+func (p *Propagation) fixpoint() bool {
+	for {
+		task, ok := p.next()
+		if !ok { return true }
+
+		if p.entailed[task.constraint] { continue }
+
+		p.running = task
+		outcome  := p.propagators[task.propagator].Propagate(task.constraint, p)
+		p.running = propagationTask{}
+		p.Runs++
+
+		switch outcome {
+		case Wipeout:
+			return false
+		case Entailed:
+			p.entailed[task.constraint] = true
+		}
+	}
+}
+
+
+// Engine
+
+/*
+ConsistencyChecker that runs its propagators to a fixpoint
+
+Only constraints on variables modified since the last Enforce are scheduled at
+first, unless SeedAll asks for every constraint in the network.
+*/
+type PropagationEngine struct {
+	Propagators []Propagator
+	SeedAll     bool
+}
+
+func (e PropagationEngine) Enforce(network *Network, trail *Trail) bool {
+	_, ok := e.Propagate(network, trail)
+	return ok
+}
+
+// like Enforce, also returning the run's state for statistics
+func (e PropagationEngine) Propagate(network *Network, trail *Trail) (*Propagation, bool) {
+	p := network.propagation
+	if p == nil || p.busy {
+		p = newPropagation()
+	}
+	if network.propagation == nil {
+		network.propagation = p
+	}
+
+	p.reset(network, trail, e.Propagators)
+	p.busy = true
+	defer func() { p.busy = false }()
+
+	modified := network.GetModifiedConstraints()
+	if e.SeedAll {
+		modified = network.constraints
+	}
+
+	for _, constraint := range modified {
+		p.enqueue(constraint)
+	}
+
+	return p, p.fixpoint()
+}
+
+
+// Built-in Propagators
+
+// Runs the constraint's own pruning, or just checks it when it has none
+type ConstraintPropagation struct{}
+
+func (ConstraintPropagation) Priority() Priority { return PriorityCheap }
+func (ConstraintPropagation) Idempotent() bool   { return true }
+
+func (ConstraintPropagation) Propagate(constraint Constraint, p *Propagation) Outcome {
+	if propagating, ok := constraint.(Propagating); ok {
+		return propagating.Propagate(p)
+	}
+
+	if !constraint.IsSatisfied() { return Wipeout }
+
+	for _, variable := range constraint.Variables() {
+		if !variable.assigned { return Consistent }
+	}
+
+	return Entailed
+}
+
+
+// Assigns unassigned variables whose domain is down to a single value
+type NakedSingles struct{}
+
+func (NakedSingles) Priority() Priority { return PriorityCheap }
+func (NakedSingles) Idempotent() bool   { return true }
+
+func (NakedSingles) Propagate(constraint Constraint, p *Propagation) Outcome {
+	for _, variable := range constraint.Variables() {
+		if variable.assigned || variable.Size() != 1 { continue }
+
+		if !p.Assign(variable, variable.Values()[0]) { return Wipeout }
+	}
+
+	return Consistent
+}
+
+
+/*
+Assigns a value to the only variable of an AllDiff that can still take it
+
+Only sound when the constraint's variables have to use up all of their values
+(as many distinct values as variables, like a Sudoku row), other constraints are skipped.
+*/
+type HiddenSingles struct{}
+
+func (HiddenSingles) Priority() Priority { return PriorityLinear }
+func (HiddenSingles) Idempotent() bool   { return false }
+
+func (HiddenSingles) Propagate(constraint Constraint, p *Propagation) Outcome {
+	allDiff, ok := constraint.(*AllDiffConstraint)
+	if !ok { return Consistent }
+
+	values, candidates := allDiff.valueSupports()
+
+	if len(values) < len(allDiff.variables) { return Wipeout }
+	if len(values) > len(allDiff.variables) { return Consistent }
+
+	for _, value := range values {
+		if len(candidates[value]) != 1 { continue }
+
+		only := candidates[value][0]
+		if only.assigned { continue }
+
+		// every other variable of the constraint already lost the value
+		causes := []Fact{}
+		if p.trail.explaining {
+			for _, other := range allDiff.variables {
+				if other != only {
+					causes = append(causes, Fact{other, value, false})
+				}
+			}
+		}
+
+		if !p.AssignBecause(only, value, causes...) { return Wipeout }
+	}
+
+	return Consistent
+}
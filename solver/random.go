@@ -0,0 +1,55 @@
+package solver
+
+import "math/rand/v2"
+
+/*
+Seeded randomness
+
+Every randomized component takes its own *rand.Rand built from a seed it keeps
+around, so a run can be replayed bit-for-bit from the recorded seed.
+*/
+
+// deterministic generator for seed
+func NewRand(seed uint64) *rand.Rand {
+	return rand.New(rand.NewPCG(seed, seed ^ 0x9e3779b97f4a7c15))
+}
+
+// fresh seed from the global source, for callers that don't pick one
+func RandomSeed() uint64 {
+	return rand.Uint64()
+}
+
+
+// Shuffles Inner's value order (DefaultValOrder when nil) with its own seeded generator
+type RandomValOrder struct {
+	Seed  uint64
+	Inner ValSelector
+
+	rng *rand.Rand
+}
+
+func NewRandomValOrder(seed uint64, inner ValSelector) *RandomValOrder {
+	return &RandomValOrder{
+		Seed:  seed,
+		Inner: inner,
+		rng:   NewRand(seed),
+	}
+}
+
+func (r *RandomValOrder) OrderValues(variable *Variable, network *Network) []int {
+	if r.rng == nil {
+		r.rng = NewRand(r.Seed)
+	}
+
+	inner := r.Inner
+	if inner == nil {
+		inner = DefaultValOrder{}
+	}
+
+	values := inner.OrderValues(variable, network)
+	r.rng.Shuffle(len(values), func(left, right int) {
+		values[left], values[right] = values[right], values[left]
+	})
+
+	return values
+}
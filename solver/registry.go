@@ -0,0 +1,271 @@
+package solver
+
+import (
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+/*
+Strategy registry
+
+Maps names to constructors for VarSelectors, ValSelectors, ConsistencyCheckers
+and whole Configurations, so CLI flags, config files or network APIs can pick
+strategies by string. A spec is a name, optionally followed by parameters:
+
+	mrv
+	random:seed=42,inner=lcv
+	sac:inner=norvig
+	pipeline:stages=forward+hidden-singles+gac
+
+Parameters that name another strategy take a plain name, nesting parameters
+isn't supported. Other packages add their own strategies with the Register
+functions, typically from an init function; names are unique per kind.
+*/
+
+// parameters of a strategy spec
+type Params map[string]string
+
+type (
+	VarSelectorFactory func(Params) (VarSelector, error)
+	ValSelectorFactory func(Params) (ValSelector, error)
+	CheckerFactory     func(Params) (ConsistencyChecker, error)
+)
+
+type registry struct {
+	lock           sync.RWMutex
+	varSelectors   map[string]VarSelectorFactory
+	valSelectors   map[string]ValSelectorFactory
+	checkers       map[string]CheckerFactory
+	configurations map[string]configurationSpec
+}
+
+// a named Configuration, as specs of its parts
+type configurationSpec struct {
+	varSelector, valSelector, checker string
+}
+
+var strategies = &registry{
+	varSelectors:   map[string]VarSelectorFactory{},
+	valSelectors:   map[string]ValSelectorFactory{},
+	checkers:       map[string]CheckerFactory{},
+	configurations: map[string]configurationSpec{},
+}
+
+// Registration, panics on a name already taken like database/sql drivers
+
+func RegisterVarSelector(name string, factory VarSelectorFactory) {
+	register(strategies.varSelectors, "variable selector", name, factory)
+}
+
+func RegisterValSelector(name string, factory ValSelectorFactory) {
+	register(strategies.valSelectors, "value selector", name, factory)
+}
+
+func RegisterChecker(name string, factory CheckerFactory) {
+	register(strategies.checkers, "checker", name, factory)
+}
+
+// a Configuration made of the three specs, see ConfigurationNamed
+func RegisterConfiguration(name, varSelector, valSelector, checker string) {
+	register(strategies.configurations, "configuration", name, configurationSpec{varSelector, valSelector, checker})
+}
+
+// Lookup
+
+func NewVarSelector(spec string) (VarSelector, error) {
+	return build(strategies.varSelectors, "variable selector", spec)
+}
+
+func NewValSelector(spec string) (ValSelector, error) {
+	return build(strategies.valSelectors, "value selector", spec)
+}
+
+func NewChecker(spec string) (ConsistencyChecker, error) {
+	return build(strategies.checkers, "checker", spec)
+}
+
+func VarSelectorNames() []string   { return names(strategies.varSelectors) }
+func ValSelectorNames() []string   { return names(strategies.valSelectors) }
+func CheckerNames() []string       { return names(strategies.checkers) }
+func ConfigurationNames() []string { return names(strategies.configurations) }
+
+// build the registered configuration called name
+func ConfigurationNamed(name string) (Configuration, error) {
+	strategies.lock.RLock()
+	spec, ok := strategies.configurations[name]
+	strategies.lock.RUnlock()
+
+	if !ok {
+		return Configuration{}, fmt.Errorf("unknown configuration %q, expected one of %v", name, ConfigurationNames())
+	}
+
+	return NewConfiguration(name, spec.varSelector, spec.valSelector, spec.checker)
+}
+
+// build a Configuration from specs of its parts
+func NewConfiguration(name, varSelector, valSelector, checker string) (Configuration, error) {
+	configuration := Configuration{Name: name}
+	var err error
+
+	if configuration.VarSelector, err = NewVarSelector(varSelector); err != nil {
+		return Configuration{}, err
+	}
+	if configuration.ValSelector, err = NewValSelector(valSelector); err != nil {
+		return Configuration{}, err
+	}
+	if configuration.ConsistencyChecker, err = NewChecker(checker); err != nil {
+		return Configuration{}, err
+	}
+
+	return configuration, nil
+}
+
+// split "name:key=value,key=value" into its name and parameters
+func ParseSpec(spec string) (string, Params, error) {
+	name, rest, _ := strings.Cut(strings.TrimSpace(spec), ":")
+	params := Params{}
+
+	if name == "" {
+		return "", nil, fmt.Errorf("strategy spec %q has no name", spec)
+	}
+
+	if rest == "" { return name, params, nil }
+
+	for _, pair := range strings.Split(rest, ",") {
+		key, value, ok := strings.Cut(pair, "=")
+		if !ok || strings.TrimSpace(key) == "" {
+			return "", nil, fmt.Errorf("strategy spec %q: expected key=value, got %q", spec, pair)
+		}
+
+		params[strings.TrimSpace(key)] = strings.TrimSpace(value)
+	}
+
+	return name, params, nil
+}
+
+// Params Accessors
+
+func (p Params) String(key, fallback string) string {
+	if value, ok := p[key]; ok { return value }
+
+	return fallback
+}
+
+func (p Params) Uint64(key string, fallback uint64) (uint64, error) {
+	value, ok := p[key]
+	if !ok { return fallback, nil }
+
+	parsed, err := strconv.ParseUint(value, 10, 64)
+	if err != nil {
+		return 0, fmt.Errorf("parameter %s: %w", key, err)
+	}
+
+	return parsed, nil
+}
+
+// Internal Helpers
+
+func register[T any](factories map[string]T, kind, name string, factory T) {
+	strategies.lock.Lock()
+	defer strategies.lock.Unlock()
+
+	if _, taken := factories[name]; taken {
+		panic(fmt.Sprintf("solver: %s %q registered twice", kind, name))
+	}
+
+	factories[name] = factory
+}
+
+func build[T any, F ~func(Params) (T, error)](factories map[string]F, kind, spec string) (T, error) {
+	var zero T
+
+	name, params, err := ParseSpec(spec)
+	if err != nil { return zero, err }
+
+	strategies.lock.RLock()
+	factory, ok := factories[name]
+	strategies.lock.RUnlock()
+
+	if !ok {
+		return zero, fmt.Errorf("unknown %s %q, expected one of %v", kind, name, names(factories))
+	}
+
+	return factory(params)
+}
+
+func names[T any](entries map[string]T) []string {
+	strategies.lock.RLock()
+	defer strategies.lock.RUnlock()
+
+	keys := make([]string, 0, len(entries))
+	for key := range entries {
+		keys = append(keys, key)
+	}
+	slices.Sort(keys)
+
+	return keys
+}
+
+
+// Built-in Strategies
+
+func init() {
+	RegisterVarSelector("first", fixed[VarSelector](FirstUnassigned{}))
+	RegisterVarSelector("mrv", fixed[VarSelector](MRV{}))
+	RegisterVarSelector("mrv-degree", fixed[VarSelector](MRVWithDegree{}))
+
+	RegisterValSelector("default", fixed[ValSelector](DefaultValOrder{}))
+	RegisterValSelector("lcv", fixed[ValSelector](LeastConstrainingValue{}))
+	RegisterValSelector("random", func(params Params) (ValSelector, error) {
+		seed, err := params.Uint64("seed", 0)
+		if err != nil { return nil, err }
+		if seed == 0 {
+			seed = RandomSeed()
+		}
+
+		inner, err := NewValSelector(params.String("inner", "default"))
+		if err != nil { return nil, err }
+
+		return NewRandomValOrder(seed, inner), nil
+	})
+
+	RegisterChecker("basic", fixed[ConsistencyChecker](BasicCheck{}))
+	RegisterChecker("forward", fixed[ConsistencyChecker](ForwardChecking{}))
+	RegisterChecker("norvig", fixed[ConsistencyChecker](NorvigCheck{}))
+	RegisterChecker("ac", fixed[ConsistencyChecker](ArcConsistency{}))
+	RegisterChecker("gac", fixed[ConsistencyChecker](AllDiffGAC{}))
+	RegisterChecker("hidden-singles", fixed[ConsistencyChecker](HiddenSingles{}))
+	RegisterChecker("naked-singles", fixed[ConsistencyChecker](NakedSingles{}))
+	RegisterChecker("sac", func(params Params) (ConsistencyChecker, error) {
+		inner, err := NewChecker(params.String("inner", "forward"))
+		if err != nil { return nil, err }
+
+		return SAC{Inner: inner}, nil
+	})
+	RegisterChecker("pipeline", func(params Params) (ConsistencyChecker, error) {
+		stages := []ConsistencyChecker{}
+
+		for _, name := range strings.Split(params.String("stages", "forward+hidden-singles+gac"), "+") {
+			stage, err := NewChecker(name)
+			if err != nil { return nil, err }
+
+			stages = append(stages, stage)
+		}
+
+		return NewPipeline(stages...), nil
+	})
+
+	RegisterConfiguration("forward", "mrv", "default", "forward")
+	RegisterConfiguration("lcv-forward", "mrv", "lcv", "forward")
+	RegisterConfiguration("norvig", "mrv", "default", "norvig")
+	RegisterConfiguration("gac", "mrv-degree", "default", "gac")
+	RegisterConfiguration("pipeline", "mrv", "default", "pipeline")
+}
+
+// factory for a strategy without parameters
+func fixed[T any](strategy T) func(Params) (T, error) {
+	return func(Params) (T, error) { return strategy, nil }
+}
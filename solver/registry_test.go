@@ -0,0 +1,87 @@
+package solver_test
+
+import (
+	"slices"
+	"testing"
+	"time"
+	"github.com/stretchr/testify/assert"
+	"sudoku-csp/solver"
+)
+
+func TestParseSpec(t *testing.T) {
+	name, params, err := solver.ParseSpec("random: seed=42, inner=lcv")
+	assert.NoError(t, err)
+	assert.Equal(t, "random", name)
+	assert.Equal(t, solver.Params{"seed": "42", "inner": "lcv"}, params)
+
+	name, params, err = solver.ParseSpec("mrv")
+	assert.NoError(t, err)
+	assert.Equal(t, "mrv", name)
+	assert.Empty(t, params)
+
+	for _, spec := range []string{"", ":seed=1", "random:seed"} {
+		_, _, err := solver.ParseSpec(spec)
+		assert.Error(t, err, spec)
+	}
+}
+
+func TestBuiltInStrategies(t *testing.T) {
+	selector, err := solver.NewVarSelector("mrv-degree")
+	assert.NoError(t, err)
+	assert.Equal(t, solver.MRVWithDegree{}, selector)
+
+	order, err := solver.NewValSelector("random:seed=7,inner=lcv")
+	assert.NoError(t, err)
+	assert.Equal(t, solver.NewRandomValOrder(7, solver.LeastConstrainingValue{}), order)
+
+	checker, err := solver.NewChecker("sac:inner=norvig")
+	assert.NoError(t, err)
+	assert.Equal(t, solver.SAC{Inner: solver.NorvigCheck{}}, checker)
+
+	checker, err = solver.NewChecker("pipeline:stages=forward+gac")
+	assert.NoError(t, err)
+	assert.Len(t, checker.(*solver.Pipeline).Stages, 2)
+
+	_, err = solver.NewValSelector("random:seed=minus-one")
+	assert.Error(t, err)
+
+	_, err = solver.NewChecker("pipeline:stages=forward+nonsense")
+	assert.Error(t, err)
+}
+
+// a strategy from outside the package
+type lastUnassigned struct{}
+
+func (lastUnassigned) Select(network *solver.Network) *solver.Variable {
+	variables := network.Variables()
+
+	for index := len(variables) - 1; index >= 0; index-- {
+		if !variables[index].Assigned() { return variables[index] }
+	}
+
+	return nil
+}
+
+func TestRegisterStrategy(t *testing.T) {
+	// the registry is global, -count=N runs this more than once
+	if !slices.Contains(solver.VarSelectorNames(), "test-last") {
+		solver.RegisterVarSelector("test-last", func(solver.Params) (solver.VarSelector, error) {
+			return lastUnassigned{}, nil
+		})
+		solver.RegisterConfiguration("test-last-forward", "test-last", "default", "forward")
+	}
+
+	assert.Contains(t, solver.VarSelectorNames(), "test-last")
+	assert.Panics(t, func() {
+		solver.RegisterVarSelector("test-last", nil)
+	})
+
+	configuration, err := solver.ConfigurationNamed("test-last-forward")
+	assert.NoError(t, err)
+	assert.Equal(t, lastUnassigned{}, configuration.VarSelector)
+
+	network := newAllDiffNetwork(4)
+	bt := configuration.NewSolver(network, solver.NewTrail())
+	assert.True(t, bt.Solve(time.Second))
+	assert.Empty(t, solver.Verify(network))
+}
@@ -0,0 +1,97 @@
+package solver
+
+import (
+	"math"
+	"time"
+)
+
+/*
+Restarts
+
+A depth-first search that made a bad early decision can spend ages below it.
+With a RestartPolicy, Solve gives each run a backtrack budget and starts over
+from the root once it is spent, with the next budget. Only useful with a
+randomized value order (see WithShuffledValues), a deterministic one retraces
+the same tree every run.
+Applies to Solve in DepthFirst mode; SolveAll never restarts, it would report
+solutions twice.
+*/
+type RestartPolicy interface {
+	// backtracks allowed in run (counted from 0), 0 for no limit
+	Backtracks(run int) int
+}
+
+// Scale times the Luby sequence 1 1 2 1 1 2 4 1 1 2 ..., Scale 0 counts as 100
+type LubyRestarts struct {
+	Scale int
+}
+
+func (r LubyRestarts) Backtracks(run int) int {
+	scale := r.Scale
+	if scale <= 0 {
+		scale = 100
+	}
+
+	return scale * luby(run + 1)
+}
+
+// First backtracks, then Factor times more every run
+type GeometricRestarts struct {
+	First  int
+	Factor float64
+}
+
+func (r GeometricRestarts) Backtracks(run int) int {
+	budget := float64(r.First) * math.Pow(r.Factor, float64(run))
+	if budget >= math.MaxInt32 { return 0 }
+
+	return max(int(budget), 1)
+}
+
+// i-th term of the Luby sequence, from 1
+func luby(i int) int {
+	for {
+		power := 1
+		for power * 2 - 1 < i {
+			power *= 2
+		}
+
+		if power * 2 - 1 == i { return power }
+
+		i -= power - 1
+	}
+}
+
+// depth-first runs under the restart policy until one finishes or a user limit runs out
+func (bt *BacktrackSolver) restartingSearch(deadline time.Time) bool {
+	level  := bt.Trail.Level()
+	limits := bt.Limits
+	defer func() { bt.Limits = limits }()
+
+	for run := 0; ; run++ {
+		budget := bt.Restarts.Backtracks(run)
+
+		bt.Limits = limits
+		if budget > 0 && (limits.Backtracks == 0 || bt.Stats.Backtracks + budget < limits.Backtracks) {
+			bt.Limits.Backtracks = bt.Stats.Backtracks + budget
+		}
+
+		search := &Search{bt: bt}
+		result := search.run(deadline)
+
+		if result == Solved {
+			bt.HasSolution = true
+			return true
+		}
+
+		if bt.Stats.Exhausted != BacktrackLimit || bt.Limits.Backtracks == limits.Backtracks {
+			return result == Stopped
+		}
+
+		bt.Trail.UndoTo(level)
+		bt.Stats.Exhausted = NoLimit
+		bt.Stats.Restarts++
+
+		if bt.Tracer != nil { bt.Tracer.Restart(run + 1) }
+	}
+}
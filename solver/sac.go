@@ -0,0 +1,76 @@
+package solver
+
+import "slices"
+
+/*
+Singleton arc consistency
+
+For every unassigned variable and value, tentatively assign it between trail
+markers, run Inner, undo, and prune the value for good if the probe wiped
+something out. Repeats until a full sweep prunes nothing.
+
+Expensive, meant as preprocessing or for telling whether a puzzle can be
+finished without guessing. Inner defaults to ForwardChecking.
+*/
+type SAC struct {
+	Inner ConsistencyChecker
+}
+
+func (s SAC) Enforce(network *Network, trail *Trail) bool {
+	inner := s.inner()
+
+	// settle pending changes so probes only see their own assignment as modified
+	if !inner.Enforce(network, trail) { return false }
+
+	for {
+		pruned := false
+
+		for _, variable := range network.variables {
+			if variable.assigned { continue }
+
+			for _, value := range slices.Clone(variable.Values()) {
+				// an earlier prune's propagation may have taken it already
+				if !variable.domain.Contains(value) { continue }
+				if s.probe(network, trail, inner, variable, value) { continue }
+
+				trail.Push(variable)
+				variable.domain.Remove(value)
+				variable.modified = true
+				variable.resized()
+				pruned = true
+				trail.explain(Reason{Fact: Fact{variable, value, false}, Rule: "solver.SAC"})
+
+				if variable.domain.Empty() {
+					trail.explainWipeout(variable)
+					return false
+				}
+				if !inner.Enforce(network, trail) { return false }
+
+				// propagation after the prune may have settled the variable already
+				if variable.assigned { break }
+			}
+		}
+
+		if !pruned { return true }
+	}
+}
+
+// true when assigning value to variable survives propagation
+func (s SAC) probe(network *Network, trail *Trail, inner ConsistencyChecker, variable *Variable, value int) bool {
+	trail.PlaceMarker()
+	trail.Push(variable)
+	variable.AssignValue(value)
+
+	ok := inner.Enforce(network, trail)
+	trail.Undo()
+
+	return ok
+}
+
+func (s SAC) inner() ConsistencyChecker {
+	if s.Inner == nil {
+		return ForwardChecking{}
+	}
+
+	return s.Inner
+}
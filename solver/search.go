@@ -0,0 +1,239 @@
+package solver
+
+import (
+	"sync/atomic"
+	"time"
+)
+
+/*
+Iterative depth-first search
+
+Keeps the decision stack explicit instead of recursing, so a search can be
+stepped one decision at a time, paused (from another goroutine too) and
+resumed, and its current path inspected, e.g. by a visualizer. Solve and
+SolveAll run their depth-first searches on it.
+
+The network and trail belong to the search between steps: don't assign or undo
+anything yourself until it is Exhausted or you are done with it.
+*/
+type Search struct {
+	bt *BacktrackSolver
+
+	frames  []searchFrame
+	descend bool // next step selects a variable below the current path
+	started bool
+	steps   int
+	result  StepResult
+	pause   atomic.Bool
+	root    []VariableState // domains the search started from, for checkpoints
+
+	// periodic checkpoints, see checkpoint.go
+	checkpointPath  string
+	checkpointEvery time.Duration
+	lastCheckpoint  time.Time
+	checkpointErr   error
+}
+
+// one decision point: the values of variable in the order they get tried
+type searchFrame struct {
+	variable *Variable
+	values   []int
+	next     int  // index of the next value to try
+	open     bool // values[next-1] is assigned above a trail marker
+}
+
+// What a Step (or Run) did
+type StepResult int
+
+const (
+	Decided   StepResult = iota // assigned a value that survived propagation
+	Failed                      // assigned a value, propagation wiped out, undone again
+	Solved                      // every variable is assigned, the next step moves on from this solution
+	Exhausted                   // no solutions left, every decision undone
+	Stopped                     // a limit ran out, see Stats.Exhausted
+	Paused                      // Pause was called, Run again to resume
+)
+
+func (r StepResult) String() string {
+	return [...]string{"decided", "failed", "solved", "exhausted", "stopped", "paused"}[r]
+}
+
+// a variable the search assigned, and the value it is trying
+type Decision struct {
+	Variable *Variable
+	Value    int
+	Tried    int // values of this decision tried so far, this one included
+	Left     int // values still to try after this one
+}
+
+// steps between clock reads in Run
+const timeCheckInterval = 64
+
+// search bt's network from its current state, resetting bt.Stats
+func NewSearch(bt *BacktrackSolver) *Search {
+	bt.Stats = Stats{}
+	return &Search{bt: bt}
+}
+
+// Accessors
+
+// the decisions leading to the current state, outermost first
+func (s *Search) Path() []Decision {
+	path := make([]Decision, 0, len(s.frames))
+
+	for _, frame := range s.frames {
+		if !frame.open { continue }
+
+		path = append(path, Decision{
+			Variable: frame.variable,
+			Value:    frame.values[frame.next - 1],
+			Tried:    frame.next,
+			Left:     len(frame.values) - frame.next,
+		})
+	}
+
+	return path
+}
+
+func (s *Search) Depth() int {
+	return len(s.Path())
+}
+
+// result of the last step
+func (s *Search) Result() StepResult {
+	return s.result
+}
+
+// no further step can find anything: Exhausted, or Stopped without Run being called again
+func (s *Search) Done() bool {
+	return s.result == Exhausted || s.result == Stopped
+}
+
+// Mutators
+
+// make a running Run return Paused after its current step, safe to call from any goroutine
+func (s *Search) Pause() {
+	s.pause.Store(true)
+}
+
+/*
+Step until a solution is found, the space is exhausted, a limit runs out or
+Pause is called, for at most timeLeft (and Limits.Time).
+
+Returns Solved, Exhausted, Stopped or Paused. Running again after Solved looks
+for the next solution; after Stopped or Paused it resumes where it left off
+with a fresh time budget (raise Limits first if a counter ran out).
+*/
+func (s *Search) Run(timeLeft time.Duration) StepResult {
+	start := time.Now()
+	defer func() { s.bt.Stats.Elapsed += time.Since(start) }()
+
+	return s.run(s.bt.deadline(timeLeft))
+}
+
+/*
+Take one decision: backtrack as far as needed, then assign the next value and
+propagate it.
+
+Returns Decided or Failed for that assignment, Solved when the step found every
+variable assigned instead, Exhausted once nothing is left to try, and Stopped
+when Limits (other than Time) ran out.
+*/
+func (s *Search) Step() StepResult {
+	bt := s.bt
+	if s.result == Exhausted { return Exhausted }
+
+	bt.Stats.Exhausted = NoLimit
+
+	if !s.started {
+		s.started, s.descend = true, true
+		s.root = captureStates(bt.Network.variables)
+		if bt.inConflict() { return s.finish(Exhausted) }
+	}
+
+	if bt.overBudget() { return s.finish(Stopped) }
+
+	s.steps++
+
+	if s.descend {
+		s.descend = false
+
+		variable := bt.Select(bt.Network)
+		if variable == nil {
+			bt.Stats.Solutions++
+			bt.traceSolution(len(s.frames))
+			return s.finish(Solved)
+		}
+
+		s.frames = append(s.frames, searchFrame{
+			variable: variable,
+			values:   bt.OrderValues(variable, bt.Network),
+		})
+	}
+
+	for len(s.frames) > 0 {
+		top := &s.frames[len(s.frames) - 1]
+
+		if top.open {
+			bt.traceBacktrack(top.variable, top.values[top.next - 1], len(s.frames) - 1)
+			bt.Trail.Undo()
+			bt.Stats.Backtracks++
+			top.open = false
+		}
+
+		if top.next == len(top.values) {
+			s.frames = s.frames[:len(s.frames) - 1]
+			continue
+		}
+
+		value := top.values[top.next]
+		top.next++
+
+		bt.Stats.Nodes++
+		bt.Trail.PlaceMarker()
+		bt.Trail.Push(top.variable)
+		top.variable.AssignValue(value)
+
+		consistent := bt.Enforce(bt.Network, bt.Trail)
+		bt.traceDecide(top.variable, value, len(s.frames) - 1, consistent)
+
+		if consistent {
+			top.open, s.descend = true, true
+			return s.finish(Decided)
+		}
+
+		bt.Trail.Undo()
+		bt.Stats.Backtracks++
+		return s.finish(Failed)
+	}
+
+	return s.finish(Exhausted)
+}
+
+// Internal Helpers
+
+func (s *Search) run(deadline time.Time) StepResult {
+	for {
+		if s.steps % timeCheckInterval == 0 {
+			now := time.Now()
+			s.autoCheckpoint(now)
+
+			if !now.Before(deadline) {
+				s.bt.Stats.Exhausted = TimeLimit
+				return s.finish(Stopped)
+			}
+		}
+
+		switch result := s.Step(); result {
+		case Solved, Exhausted, Stopped:
+			return result
+		}
+
+		if s.pause.Swap(false) { return s.finish(Paused) }
+	}
+}
+
+func (s *Search) finish(result StepResult) StepResult {
+	s.result = result
+	return result
+}
@@ -0,0 +1,237 @@
+package solver
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"math"
+	"slices"
+	"time"
+)
+
+/*
+Algorithm selection
+
+A RuleTable maps Features to the name of a Configuration: the first rule whose
+ranges contain the features wins, Default otherwise. Tables are plain JSON, and
+TrainRules rebuilds one from benchmark records (features, configuration, time)
+by keeping the fastest configuration for each board side and filled quarter.
+*/
+
+// selectors and checker to build a BacktrackSolver from, named ones are in the registry (see registry.go)
+type Configuration struct {
+	Name string
+
+	VarSelector
+	ValSelector
+	ConsistencyChecker
+}
+
+func (c Configuration) NewSolver(network *Network, trail *Trail) *BacktrackSolver {
+	return NewBacktrackSolver(network, trail, c.VarSelector, c.ValSelector, c.ConsistencyChecker)
+}
+
+
+// Applies to features within every range it sets, zero bounds are open
+type Rule struct {
+	MinDomain int     `json:",omitempty"`
+	MaxDomain int     `json:",omitempty"`
+	MinFilled float64 `json:",omitempty"`
+	MaxFilled float64 `json:",omitempty"` // exclusive, unless 1
+
+	Configuration string
+}
+
+func (r Rule) Matches(features Features) bool {
+	filled := features.Filled()
+
+	switch {
+	case r.MinDomain > 0 && features.MaxDomain < r.MinDomain:
+		return false
+	case r.MaxDomain > 0 && features.MaxDomain > r.MaxDomain:
+		return false
+	case filled < r.MinFilled:
+		return false
+	case r.MaxFilled > 0 && filled >= r.MaxFilled && r.MaxFilled < 1:
+		return false
+	}
+
+	return true
+}
+
+type RuleTable struct {
+	Rules   []Rule
+	Default string
+}
+
+/*
+Hand-picked thresholds, not the output of TrainRules: forward checking for
+boards up to 9x9, GAC for larger boards that propagation leaves less than 3/4
+filled, forward checking for the rest. Record runs with the CLI's --record and
+retrain with --train to get a table backed by data.
+*/
+var DefaultRules = RuleTable{
+	Rules: []Rule{
+		{MaxDomain: 9, Configuration: "forward"},
+		{MinDomain: 16, MaxFilled: 0.75, Configuration: "gac"},
+	},
+	Default: "forward",
+}
+
+// name of the configuration for features
+func (t RuleTable) Select(features Features) string {
+	for _, rule := range t.Rules {
+		if rule.Matches(features) { return rule.Configuration }
+	}
+
+	return t.Default
+}
+
+// the configuration Select picks, built
+func (t RuleTable) Configure(features Features) (Configuration, error) {
+	return ConfigurationNamed(t.Select(features))
+}
+
+func ReadRuleTable(reader io.Reader) (RuleTable, error) {
+	table := RuleTable{}
+	if err := json.NewDecoder(reader).Decode(&table); err != nil {
+		return RuleTable{}, fmt.Errorf("reading rule table: %w", err)
+	}
+
+	return table, nil
+}
+
+func (t RuleTable) Write(writer io.Writer) error {
+	encoder := json.NewEncoder(writer)
+	encoder.SetIndent("", "  ")
+
+	return encoder.Encode(t)
+}
+
+
+// Training
+
+// One benchmark run, written as a JSON line per run
+type BenchmarkRecord struct {
+	Configuration string
+	Features      Features
+	Elapsed       time.Duration
+	Solved        bool // false when the run was cut short
+}
+
+func WriteBenchmarkRecord(writer io.Writer, record BenchmarkRecord) error {
+	return json.NewEncoder(writer).Encode(record)
+}
+
+func ReadBenchmarkRecords(reader io.Reader) ([]BenchmarkRecord, error) {
+	records := []BenchmarkRecord{}
+	decoder := json.NewDecoder(reader)
+
+	for decoder.More() {
+		record := BenchmarkRecord{}
+		if err := decoder.Decode(&record); err != nil {
+			return nil, fmt.Errorf("reading benchmark record %d: %w", len(records) + 1, err)
+		}
+
+		records = append(records, record)
+	}
+
+	return records, nil
+}
+
+// cells of the training grid: board side, and which quarter of it propagation fills
+type trainingCell struct {
+	domain  int
+	quarter int
+}
+
+func cellOf(features Features) trainingCell {
+	return trainingCell{features.MaxDomain, min(int(features.Filled() * 4), 3)}
+}
+
+/*
+Rebuild a rule table from benchmark records
+
+Records are grouped by MaxDomain and filled quarter. Each group gets a rule for
+the configuration with the least mean time per run, a run that wasn't solved
+counting as twice the slowest run in the group, so a configuration recorded
+more often isn't penalized for it. Default is the configuration that wins most
+groups.
+*/
+func TrainRules(records []BenchmarkRecord) RuleTable {
+	totals := map[trainingCell]map[string]time.Duration{}
+	runs   := map[trainingCell]map[string]int{}
+	worst  := map[trainingCell]time.Duration{}
+
+	for _, record := range records {
+		cell := cellOf(record.Features)
+		if totals[cell] == nil {
+			totals[cell] = map[string]time.Duration{}
+			runs[cell]   = map[string]int{}
+		}
+
+		worst[cell] = max(worst[cell], record.Elapsed)
+	}
+
+	for _, record := range records {
+		cell    := cellOf(record.Features)
+		elapsed := record.Elapsed
+		if !record.Solved {
+			elapsed = 2 * worst[cell]
+		}
+
+		totals[cell][record.Configuration] += elapsed
+		runs[cell][record.Configuration]++
+	}
+
+	cells := make([]trainingCell, 0, len(totals))
+	for cell := range totals {
+		cells = append(cells, cell)
+	}
+	slices.SortFunc(cells, func(left, right trainingCell) int {
+		if left.domain != right.domain { return left.domain - right.domain }
+		return left.quarter - right.quarter
+	})
+
+	table := RuleTable{}
+	wins  := map[string]int{}
+
+	for _, cell := range cells {
+		best, bestTime := "", time.Duration(math.MaxInt64)
+
+		for _, name := range sortedKeys(totals[cell]) {
+			mean := totals[cell][name] / time.Duration(runs[cell][name])
+
+			if mean < bestTime {
+				best, bestTime = name, mean
+			}
+		}
+
+		wins[best]++
+		table.Rules = append(table.Rules, Rule{
+			MinDomain:     cell.domain,
+			MaxDomain:     cell.domain,
+			MinFilled:     float64(cell.quarter) / 4,
+			MaxFilled:     float64(cell.quarter + 1) / 4,
+			Configuration: best,
+		})
+	}
+
+	for _, name := range sortedKeys(wins) {
+		if table.Default == "" || wins[name] > wins[table.Default] {
+			table.Default = name
+		}
+	}
+
+	return table
+}
+
+func sortedKeys[T any](values map[string]T) []string {
+	keys := make([]string, 0, len(values))
+	for key := range values {
+		keys = append(keys, key)
+	}
+	slices.Sort(keys)
+
+	return keys
+}
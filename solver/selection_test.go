@@ -0,0 +1,72 @@
+package solver_test
+
+import (
+	"bytes"
+	"testing"
+	"time"
+	"github.com/stretchr/testify/assert"
+	"sudoku-csp/solver"
+)
+
+func TestConfigurationNamed(t *testing.T) {
+	for _, name := range solver.ConfigurationNames() {
+		configuration, err := solver.ConfigurationNamed(name)
+		assert.NoError(t, err)
+		assert.Equal(t, name, configuration.Name)
+	}
+
+	_, err := solver.ConfigurationNamed("psychic")
+	assert.Error(t, err)
+}
+
+func TestTrainRules(t *testing.T) {
+	small := solver.Features{Variables: 16, Propagated: 4, MaxDomain: 4}
+	large := solver.Features{Variables: 625, Propagated: 100, MaxDomain: 25}
+
+	records := []solver.BenchmarkRecord{
+		{Configuration: "forward", Features: small, Elapsed: time.Millisecond, Solved: true},
+		{Configuration: "gac", Features: small, Elapsed: 3 * time.Millisecond, Solved: true},
+		{Configuration: "forward", Features: large, Elapsed: time.Second, Solved: false},
+		{Configuration: "gac", Features: large, Elapsed: 2 * time.Second, Solved: true},
+	}
+
+	table := solver.TrainRules(records)
+	assert.Equal(t, "forward", table.Select(small))
+	assert.Equal(t, "gac", table.Select(large), "an unsolved run counts as slower than any solved one")
+
+	// unseen features fall back to the default
+	assert.Contains(t, []string{"forward", "gac"}, table.Select(solver.Features{MaxDomain: 9}))
+
+	var buffer bytes.Buffer
+	assert.NoError(t, table.Write(&buffer))
+
+	read, err := solver.ReadRuleTable(&buffer)
+	assert.NoError(t, err)
+	assert.Equal(t, table, read)
+}
+
+func TestTrainRulesComparesMeanTimes(t *testing.T) {
+	features := solver.Features{Variables: 81, Propagated: 30, MaxDomain: 9}
+
+	// gac was picked (and recorded) five times as often, each run faster than forward's
+	records := []solver.BenchmarkRecord{
+		{Configuration: "forward", Features: features, Elapsed: 10 * time.Millisecond, Solved: true},
+	}
+	for range 5 {
+		records = append(records, solver.BenchmarkRecord{Configuration: "gac", Features: features, Elapsed: 4 * time.Millisecond, Solved: true})
+	}
+
+	assert.Equal(t, "gac", solver.TrainRules(records).Select(features))
+}
+
+func TestBenchmarkRecordsRoundTrip(t *testing.T) {
+	var buffer bytes.Buffer
+	record := solver.BenchmarkRecord{Configuration: "norvig", Features: solver.Features{Variables: 81}, Elapsed: time.Second, Solved: true}
+
+	assert.NoError(t, solver.WriteBenchmarkRecord(&buffer, record))
+	assert.NoError(t, solver.WriteBenchmarkRecord(&buffer, record))
+
+	records, err := solver.ReadBenchmarkRecords(&buffer)
+	assert.NoError(t, err)
+	assert.Equal(t, []solver.BenchmarkRecord{record, record}, records)
+}
@@ -0,0 +1,104 @@
+package solver
+
+import "fmt"
+
+// one variable's saved state, its live values in snapshot.values[start:start+size]
+type snapshotEntry struct {
+	variable *Variable
+	domain   *Domain
+	size     int
+	start    int
+	assigned bool
+	modified bool
+}
+
+/*
+Saved live values and assignment flags of every variable in a Network
+
+Only each domain's live region is kept, in one flat buffer. Restoring swaps the
+saved values back to the front of their domain's sparse set (updating its
+positions) and resets the size, so snapshots can be restored in any order:
+whatever was removed, restored or reshuffled since, exactly the captured values
+come back live. Trail entries pushed before the snapshot stay valid as long as
+no restore in between brought back values removed before this capture.
+*/
+type snapshot struct {
+	entries []snapshotEntry
+	values  []int
+}
+
+func (s *snapshot) capture(variables []*Variable) {
+	s.entries = s.entries[:0]
+	s.values  = s.values[:0]
+
+	for _, variable := range variables {
+		domain := variable.domain
+		start  := len(s.values)
+		s.values = append(s.values, domain.Values()...)
+
+		s.entries = append(s.entries, snapshotEntry{
+			variable: variable,
+			domain:   domain,
+			size:     domain.size,
+			start:    start,
+			assigned: variable.assigned,
+			modified: variable.modified,
+		})
+	}
+}
+
+func (s *snapshot) restore() {
+	for _, entry := range s.entries {
+		domain := entry.domain
+
+		for index, value := range s.values[entry.start:entry.start + entry.size] {
+			domain.swap(index, domain.position(value))
+		}
+		domain.size = entry.size
+
+		entry.variable.domain   = entry.domain
+		entry.variable.assigned = entry.assigned
+		entry.variable.modified = entry.modified
+		entry.variable.resized()
+	}
+}
+
+// Mutators
+
+// save all domains and assignment flags under name, replacing any snapshot with that name
+func (n *Network) Snapshot(name string) {
+	saved, ok := n.snapshots[name]
+	if !ok {
+		saved = &snapshot{}
+		n.snapshots[name] = saved
+	}
+
+	saved.capture(n.variables)
+}
+
+/*
+Put every variable back the way it was when the snapshot was taken
+
+Restoring doesn't touch the Trail: entries pushed after the snapshot describe
+states that no longer exist, so undo past them or Clear the trail afterwards.
+*/
+func (n *Network) Restore(name string) error {
+	saved, ok := n.snapshots[name]
+	if !ok {
+		return fmt.Errorf("no snapshot named %q", name)
+	}
+
+	saved.restore()
+	return nil
+}
+
+func (n *Network) DropSnapshot(name string) {
+	delete(n.snapshots, name)
+}
+
+// Accessors
+
+func (n *Network) HasSnapshot(name string) bool {
+	_, ok := n.snapshots[name]
+	return ok
+}
@@ -0,0 +1,135 @@
+package solver_test
+
+import (
+	"testing"
+	"time"
+	"github.com/stretchr/testify/assert"
+	"sudoku-csp/solver"
+)
+
+func newAllDiffNetwork(count int) *solver.Network {
+	values := make([]int, count)
+	for index := range values {
+		values[index] = index + 1
+	}
+
+	network   := solver.NewNetwork()
+	variables := make([]*solver.Variable, count)
+	for index := range variables {
+		variables[index] = solver.NewVariable("", values, nil)
+		network.AddVariable(variables[index])
+	}
+	network.AddConstraint(solver.NewAllDiffConstraint(variables))
+
+	return network
+}
+
+func TestSnapshotResetBetweenSolves(t *testing.T) {
+	network := newAllDiffNetwork(4)
+	trail   := solver.NewTrail()
+	network.Snapshot("fresh")
+
+	for range 3 {
+		bt := solver.NewBacktrackSolver(network, trail, solver.MRV{}, solver.DefaultValOrder{}, solver.ForwardChecking{})
+		assert.True(t, bt.Solve(time.Second))
+
+		for _, variable := range network.Variables() {
+			assert.True(t, variable.Assigned())
+		}
+
+		assert.NoError(t, network.Restore("fresh"))
+		trail.Clear()
+
+		for _, variable := range network.Variables() {
+			assert.False(t, variable.Assigned())
+			assert.Equal(t, 4, variable.Size())
+		}
+	}
+}
+
+func TestSnapshotKeepsEarlierTrailValid(t *testing.T) {
+	network   := newAllDiffNetwork(3)
+	variables := network.Variables()
+	trail     := solver.NewTrail()
+
+	trail.PlaceMarker()
+	trail.Push(variables[0])
+	variables[0].AssignValue(1)
+	network.Snapshot("hypothesis")
+
+	// try a move, then throw it away
+	trail.PlaceMarker()
+	trail.Push(variables[1])
+	variables[1].AssignValue(2)
+	assert.NoError(t, network.Restore("hypothesis"))
+
+	assert.True(t, variables[0].Assigned())
+	assert.False(t, variables[1].Assigned())
+	assert.Equal(t, 3, variables[1].Size())
+
+	// the marker placed before the snapshot still rewinds correctly
+	trail.Undo()
+	trail.Undo()
+	assert.False(t, variables[0].Assigned())
+	assert.ElementsMatch(t, []int{1, 2, 3}, variables[0].Values())
+
+	assert.Error(t, network.Restore("missing"))
+	network.DropSnapshot("hypothesis")
+	assert.False(t, network.HasSnapshot("hypothesis"))
+}
+
+func TestSnapshotRestoresSizesAfterRemovals(t *testing.T) {
+	network   := newAllDiffNetwork(5)
+	variable  := network.Variables()[2]
+	variable.RemoveValueFromDomain(4)
+	network.Snapshot("pruned")
+
+	for _, value := range []int{1, 5, 3} {
+		variable.RemoveValueFromDomain(value)
+	}
+	assert.Equal(t, []int{2}, variable.Values())
+
+	assert.NoError(t, network.Restore("pruned"))
+	assert.ElementsMatch(t, []int{1, 2, 3, 5}, variable.Values())
+	assert.NotContains(t, variable.Values(), 4)
+}
+
+func TestSnapshotRestoresOutOfOrder(t *testing.T) {
+	network  := newAllDiffNetwork(5)
+	variable := network.Variables()[0]
+
+	variable.RemoveValueFromDomain(5)
+	network.Snapshot("A")
+	variable.RemoveValueFromDomain(3)
+	network.Snapshot("B")
+
+	assert.NoError(t, network.Restore("A"))
+	assert.ElementsMatch(t, []int{1, 2, 3, 4}, variable.Values())
+
+	variable.RemoveValueFromDomain(1)
+	assert.NoError(t, network.Restore("B"))
+	assert.ElementsMatch(t, []int{1, 2, 4}, variable.Values())
+
+	variable.RemoveValueFromDomain(2)
+	assert.NoError(t, network.Restore("A"))
+	assert.ElementsMatch(t, []int{1, 2, 3, 4}, variable.Values())
+	assert.NotContains(t, variable.Values(), 5)
+
+	assert.NoError(t, network.Restore("B"))
+	assert.ElementsMatch(t, []int{1, 2, 4}, variable.Values())
+}
+
+func TestSnapshotRestoreSkipsValuesRemovedSinceOlderCapture(t *testing.T) {
+	network  := newAllDiffNetwork(5)
+	variable := network.Variables()[0]
+
+	network.Snapshot("A")
+	variable.RemoveValueFromDomain(5)
+	network.Snapshot("B")
+
+	assert.NoError(t, network.Restore("A"))
+	variable.RemoveValueFromDomain(1)
+	assert.NoError(t, network.Restore("B"))
+
+	assert.ElementsMatch(t, []int{1, 2, 3, 4}, variable.Values())
+}
@@ -0,0 +1,74 @@
+package solver
+
+/*
+Soft constraints and Max-CSP
+
+Soft constraints live next to the hard ones but are never enforced by the
+checkers: violating one only costs its weight. A Max-CSP solve looks for the
+solution of the hard constraints with the least total violated weight.
+
+Bounds rely on IsSatisfied never turning true again once it reported a
+violation as more variables get assigned, which holds for AllDiff and Equals.
+*/
+type SoftConstraint struct {
+	Constraint
+	Weight int
+}
+
+// Mutators
+
+func (n *Network) AddSoftConstraint(constraint Constraint, weight int) *SoftConstraint {
+	soft := &SoftConstraint{
+		Constraint: constraint,
+		Weight:     weight,
+	}
+
+	n.softConstraints = append(n.softConstraints, soft)
+	return soft
+}
+
+// Accessors
+
+func (n *Network) SoftConstraints() []*SoftConstraint {
+	return n.softConstraints
+}
+
+// soft constraints the current assignment violates
+func (n *Network) ViolatedSoftConstraints() []*SoftConstraint {
+	violated := []*SoftConstraint{}
+
+	for _, soft := range n.softConstraints {
+		if !soft.IsSatisfied() {
+			violated = append(violated, soft)
+		}
+	}
+
+	return violated
+}
+
+// total weight of violated soft constraints
+func (n *Network) Violation() int {
+	total := 0
+	for _, soft := range n.ViolatedSoftConstraints() {
+		total += soft.Weight
+	}
+
+	return total
+}
+
+
+// Objective: weight of violated soft constraints, already violated ones bound any completion
+type ViolationObjective struct{}
+
+func (ViolationObjective) Evaluate(network *Network) int {
+	return network.Violation()
+}
+
+func (ViolationObjective) Bound(network *Network, sense Sense) int {
+	return network.Violation()
+}
+
+// branch-and-bound minimizing violated soft weight, BestValue ends up as that weight
+func NewMaxCSPSolver(bt *BacktrackSolver) *OptimizingSolver {
+	return NewOptimizingSolver(bt, ViolationObjective{}, Minimize)
+}
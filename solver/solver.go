@@ -26,12 +26,21 @@ type BacktrackSolver struct {
 	Network     *Network
 	Trail       *Trail
 	HasSolution bool
+	Limits      Limits
+	Stats       Stats
+	Mode        SearchMode    // DepthFirst unless set, see discrepancy.go
+	Tracer      Tracer        // nil unless set, see trace.go
+	Restarts    RestartPolicy // nil unless set, see restart.go
+	Seed        uint64        // seed New was given or picked, pass it to WithSeed to replay a run
 
 	VarSelector         // Select()
 	ValSelector         // OrderValues()
 	ConsistencyChecker  // Enforce()
+
+	levels []assumptionLevel // open Push levels, see assumptions.go
 }
 
+// positional form of New, for callers that build their own Trail
 func NewBacktrackSolver(
 	network     *Network,
 	trail       *Trail,
@@ -39,57 +48,73 @@ func NewBacktrackSolver(
 	valSelector ValSelector,
 	checker     ConsistencyChecker,
 ) *BacktrackSolver {
-	return &BacktrackSolver{	
-		Network:            network,
-		Trail:              trail,
-		VarSelector:        varSelector,
-		ValSelector:        valSelector,
-		ConsistencyChecker: checker,
-		HasSolution:        false,
-	}
+	return New(
+		network,
+		WithTrail(trail),
+		WithVarSelector(varSelector),
+		WithValSelector(valSelector),
+		WithChecker(checker),
+	)
 }
 
 
 // Solver Logic
 
-// check that this can be converted into a boolean function. we only return -1 and 0? so it can be true and false?
 func (bt *BacktrackSolver) Solve(timeLeft time.Duration) bool {
-	if timeLeft <= 0 {
-		return false
-	} else if bt.HasSolution {
-		return true
-	}
-	
+	bt.Stats = Stats{}
+	if bt.HasSolution { return true }
+
 	start := time.Now()
-	
-	variable := bt.Select(bt.Network)
-	if variable == nil {
-		bt.HasSolution = true
-		return true
+
+	bt.run(bt.deadline(timeLeft), nil)
+	bt.Stats.Elapsed = time.Since(start)
+
+	return bt.HasSolution
+}
+
+/*
+Enumerate solutions, calling onSolution with each one assigned in the network,
+until onSolution returns false, Limits.Solutions is reached or the space is
+exhausted. Returns the number of solutions found.
+
+When the enumeration is cut short the last solution stays assigned, otherwise
+the network is left as it was.
+*/
+func (bt *BacktrackSolver) SolveAll(timeLeft time.Duration, onSolution func() bool) int {
+	bt.Stats = Stats{}
+	start   := time.Now()
+
+	if onSolution == nil {
+		onSolution = func() bool { return true }
 	}
 
-	for _, value := range bt.OrderValues(variable, bt.Network) {
-		bt.Trail.PlaceMarker()
-		bt.Trail.Push(variable)
-		variable.AssignValue(value)
+	bt.run(bt.deadline(timeLeft), onSolution)
+	bt.Stats.Elapsed = time.Since(start)
 
-		if bt.Enforce(bt.Network, bt.Trail) {
-			remainingTime := timeLeft - time.Since(start)
+	return bt.Stats.Solutions
+}
 
-			if bt.Solve(remainingTime) {
-				return true
-			}
-		}
+// search in bt.Mode, true once it should stop (solution found, enumeration over, or a limit hit)
+func (bt *BacktrackSolver) run(deadline time.Time, onSolution func() bool) bool {
+	if bt.Mode != DepthFirst {
+		return bt.discrepancySearch(deadline, onSolution)
+	}
+	if bt.Restarts != nil && onSolution == nil {
+		return bt.restartingSearch(deadline)
+	}
 
-		if bt.HasSolution {
+	search := NewSearch(bt)
+
+	for search.run(deadline) == Solved {
+		if onSolution == nil {
+			bt.HasSolution = true
 			return true
 		}
 
-		bt.Trail.Undo()
+		if !onSolution() { return true }
 	}
 
-	// fmt.Println("darn no solution")
-	return false
+	return search.Result() == Stopped
 }
 
 
@@ -101,7 +126,7 @@ type FirstUnassigned struct{}
 
 func (FirstUnassigned) Select(network *Network) *Variable {
 	for _, variable := range network.variables {
-		if !variable.assigned { return variable }
+		if !variable.assigned && !variable.outOfScope { return variable }
 	}
 
 	return nil
@@ -111,43 +136,20 @@ func (FirstUnassigned) Select(network *Network) *Variable {
 type MRV struct{}
 
 func (MRV) Select(network *Network) *Variable {
-	var bestVar *Variable
+	network.Finalize()
 
-	minSize := -1
-	for _, variable := range network.variables {
-		if !variable.assigned {
-			size := variable.Size()
-
-			if minSize == -1 || size < minSize {
-				minSize = size
-				bestVar = variable
-			}
-		}
-	}
-
-	return bestVar
+	// ties go to the variable added first, like a scan in network order
+	return network.buckets.first()
 }
 
 
 type MRVWithDegree struct{}
 
 func (MRVWithDegree) Select(network *Network) *Variable {
-	var candidates []*Variable
-	minDomainSize := -1
-
-	for _, variable := range network.variables {
-		if variable.assigned { continue }
-
-		size := variable.Size()
-		if minDomainSize == -1 || size < minDomainSize {
-			minDomainSize = size
-			candidates = []*Variable{variable}
-		} else {
-			candidates = append(candidates, variable)
-		}
-	}
+	candidates := network.SmallestUnassigned()
 
-	if len(candidates) == 1 {
+	if len(candidates) <= 1 {
+		if len(candidates) == 0 { return nil }
 		return candidates[0]
 	}
 
@@ -162,7 +164,7 @@ func (MRVWithDegree) Select(network *Network) *Variable {
 			if !neighbor.assigned { degree++ }
 		}
 
-		if degree > maxDegree {
+		if degree > maxDegree || degree == maxDegree && variable.index < best.index {
 			maxDegree, best = degree, variable
 		}
 	}
@@ -173,19 +175,27 @@ func (MRVWithDegree) Select(network *Network) *Variable {
 
 // 2) Value Selectors
 
+// Ascending values, the domain's own order shifts as values are removed and restored
 type DefaultValOrder struct{}
 
 func (DefaultValOrder) OrderValues(variable *Variable, network *Network) []int {
-	values := variable.Values()
-	return append([]int{}, values...)
+	values := append([]int{}, variable.Values()...)
+	sort.Ints(values)
+
+	return values
 }
 
 
 type LeastConstrainingValue struct{}
 
 func (LeastConstrainingValue) OrderValues(variable *Variable, network *Network) []int {
-	neighbors   := network.GetNeighbors(variable)
-	valueImpact := make(map[int]int)
+	neighbors := network.GetNeighbors(variable)
+
+	type valueImpactPair struct {
+		value  int
+		impact int
+	}
+	var pairs []valueImpactPair
 
 	for _, value := range variable.Values() {
 		impact := 0
@@ -196,21 +206,16 @@ func (LeastConstrainingValue) OrderValues(variable *Variable, network *Network)
 			}
 		}
 
-		valueImpact[value] = impact
-	}
-
-	type valueImpactPair struct {
-		value  int
-		impact int
-	}
-	var pairs []valueImpactPair
-
-	for value, impact := range valueImpact {
 		pairs = append(pairs, valueImpactPair{value, impact})
 	}
 
+	// ties broken by value so the order never depends on domain layout
 	sort.Slice(pairs, func(left, right int) bool {
-		return pairs[left].impact < pairs[right].impact
+		if pairs[left].impact != pairs[right].impact {
+			return pairs[left].impact < pairs[right].impact
+		}
+
+		return pairs[left].value < pairs[right].value
 	})
 
 	ordered := make([]int, len(pairs))
@@ -235,126 +240,39 @@ func (BasicCheck) Enforce(network *Network, trail *Trail) bool {
 }
 
 
-type ForwardChecking struct{}
-
-func (ForwardChecking) Enforce(network *Network, trail *Trail) bool {
-	for _, modifiedConstraint := range network.GetModifiedConstraints() {
-		for _, variable := range modifiedConstraint.Variables() {
-			if !variable.assigned { continue }
-
-			value := variable.Assignment()
-			for _, neighbor := range network.GetNeighbors(variable) {
-				if !neighbor.domain.Contains(value) { continue }
-
-				trail.Push(neighbor)
-				neighbor.domain.Remove(value)
+// built on the propagation engine, see propagation.go
 
-				if neighbor.domain.Empty() { return false }
-
-				neighbor.modified = true
-			}
-		}
+var (
+	forwardChecking = PropagationEngine{
+		Propagators: []Propagator{ConstraintPropagation{}},
 	}
-
-	for _, constraint := range network.constraints {
-		if !constraint.IsSatisfied() { return false }
+	norvigCheck = PropagationEngine{
+		Propagators: []Propagator{ConstraintPropagation{}, HiddenSingles{}},
+	}
+	arcConsistency = PropagationEngine{
+		Propagators: []Propagator{ConstraintPropagation{}, NakedSingles{}},
+		SeedAll:     true,
 	}
+)
 
-	return true
+type ForwardChecking struct{}
+
+func (ForwardChecking) Enforce(network *Network, trail *Trail) bool {
+	return forwardChecking.Enforce(network, trail)
 }
 
 
+// forward checking + hidden singles
 type NorvigCheck struct{}
 
 func (NorvigCheck) Enforce(network *Network, trail *Trail) bool {
-	// 1) forward checking
-	for _, constraint := range network.GetModifiedConstraints() {
-		for _, variable := range constraint.Variables() {
-			if !variable.assigned { continue }
-
-			value := variable.Assignment()
-
-			for _, neighbor := range network.GetNeighbors(variable) {
-				if !neighbor.domain.Contains(value) { continue }
-
-				trail.Push(neighbor)
-				neighbor.domain.Remove(value)
-
-				if neighbor.domain.Empty() { return false }
-
-				neighbor.modified = true
-			}
-		}
-	}
-
-	// 2) only-choice rule
-	for _, constraint := range network.Constraints() {
-		valueVariablesMap := make(map[int][]*Variable)
-
-		for _, variable := range constraint.Variables() {
-			if variable.assigned { continue }
-
-			for _, value := range variable.domain.values {
-				valueVariablesMap[value] = append(valueVariablesMap[value], variable)
-			}
-		}
-
-		for value, variables := range valueVariablesMap {
-			if len(variables) != 1 { continue }
-
-			leadVariable := variables[0]
-			trail.Push(leadVariable)
-
-			leadVariable.AssignValue(value)
-			leadVariable.assigned = true
-		}
-	}
-
-	for _, constraint := range network.Constraints() {
-		if !constraint.IsSatisfied() { return false }
-	}
-
-	return true
+	return norvigCheck.Enforce(network, trail)
 }
 
 
+// forward checking + naked singles, starting from every constraint
 type ArcConsistency struct{}
 
 func (ArcConsistency) Enforce(network *Network, trail *Trail) bool {
-	queue := []*Variable{}
-
-	for _, variable := range network.variables {
-		if !variable.assigned { continue }	
-
-		queue = append(queue, variable)
-	}
-
-	for len(queue) > 0 {
-		variable := queue[0]
-		value    := variable.Assignment()
-		queue     = queue[1:]
-	
-		for _, neighbor := range network.GetNeighbors(variable) {
-			if neighbor.assigned || !neighbor.domain.Contains(value) { continue }
-
-			trail.Push(neighbor)
-			neighbor.domain.Remove(value)
-
-			if neighbor.domain.Empty() { return false }
-			neighbor.modified = true
-
-			if neighbor.Size() != 1 { continue }
-
-			valueToAssign := neighbor.Values()[0]
-			neighbor.AssignValue(valueToAssign)
-			queue = append(queue, neighbor)
-		}
-	}
-
-	for _, constraint := range network.constraints {
-		if !constraint.IsSatisfied() { return false }
-	}
-
-	return true
+	return arcConsistency.Enforce(network, trail)
 }
-
@@ -0,0 +1,68 @@
+package solver
+
+import (
+	"fmt"
+	"io"
+	"strings"
+)
+
+// Watches a search's decisions, set through BacktrackSolver.Tracer or WithTracer
+type Tracer interface {
+	// value was assigned to variable at depth (0 for the first decision), ok when it survived propagation
+	Decide(variable *Variable, value, depth int, ok bool)
+
+	// a value that survived propagation is taken back off
+	Backtrack(variable *Variable, value, depth int)
+
+	Solution(depth int)
+
+	// the search gave up on the current tree to start over, see RestartPolicy
+	Restart(run int)
+}
+
+// writes one indented line per event
+type LogTracer struct {
+	Out io.Writer
+}
+
+func (t LogTracer) Decide(variable *Variable, value, depth int, ok bool) {
+	status := "ok"
+	if !ok {
+		status = "wipeout"
+	}
+
+	fmt.Fprintf(t.Out, "%s%s = %d (%s)\n", strings.Repeat("  ", depth), variable.Name, value, status)
+}
+
+func (t LogTracer) Backtrack(variable *Variable, value, depth int) {
+	fmt.Fprintf(t.Out, "%s%s != %d\n", strings.Repeat("  ", depth), variable.Name, value)
+}
+
+func (t LogTracer) Solution(depth int) {
+	fmt.Fprintf(t.Out, "%ssolution\n", strings.Repeat("  ", depth))
+}
+
+func (t LogTracer) Restart(run int) {
+	fmt.Fprintf(t.Out, "restart %d\n", run)
+}
+
+
+// Internal Helpers
+
+func (bt *BacktrackSolver) traceDecide(variable *Variable, value, depth int, ok bool) {
+	if bt.Tracer != nil {
+		bt.Tracer.Decide(variable, value, depth, ok)
+	}
+}
+
+func (bt *BacktrackSolver) traceBacktrack(variable *Variable, value, depth int) {
+	if bt.Tracer != nil {
+		bt.Tracer.Backtrack(variable, value, depth)
+	}
+}
+
+func (bt *BacktrackSolver) traceSolution(depth int) {
+	if bt.Tracer != nil {
+		bt.Tracer.Solution(depth)
+	}
+}
@@ -2,17 +2,31 @@ package solver
 
 import "slices"
 
+// a variable's state before a change: which domain it pointed at, how many of its values were live, and whether it was assigned
 type trailEntry struct {
 	variable *Variable
 	domain   *Domain
+	size     int
+	assigned bool
 }
 
-// Represents changes for easier forward propagation
+/*
+Represents changes for easier forward propagation
+
+Entries are fixed-size deltas instead of domain clones: domains are sparse sets,
+so restoring the recorded size undoes every removal made since the Push.
+*/
 type Trail struct {
 	stack     []trailEntry
 	markers   []int
 	numPushes int
 	numUndoes int
+
+	// explanation log, see explain.go
+	explaining  bool
+	reasons     []Reason
+	reasonMarks []int
+	lastWipeout *Explanation
 }
 
 func NewTrail() *Trail {
@@ -28,6 +42,11 @@ func (t *Trail) Copy() *Trail {
 		markers:   slices.Clone(t.markers),
 		numPushes: t.numPushes,
 		numUndoes: t.numUndoes,
+
+		explaining:  t.explaining,
+		reasons:     slices.Clone(t.reasons),
+		reasonMarks: slices.Clone(t.reasonMarks),
+		lastWipeout: t.lastWipeout,
 	}
 }
 
@@ -36,15 +55,32 @@ func (t *Trail) Size() int {
 	return len(t.stack)
 }
 
+// number of markers placed and not undone yet
+func (t *Trail) Level() int {
+	return len(t.markers)
+}
+
+func (t *Trail) Pushes() int {
+	return t.numPushes
+}
+
+func (t *Trail) Undoes() int {
+	return t.numUndoes
+}
+
 // Mutators
 func (t *Trail) PlaceMarker() {
-	t.markers = append(t.markers, len(t.stack))
+	t.markers     = append(t.markers, len(t.stack))
+	t.reasonMarks = append(t.reasonMarks, len(t.reasons))
 }
 
+// record the variable's current state, call before changing its domain
 func (t *Trail) Push(variable *Variable) {
 	entry := trailEntry{
 		variable: variable,
-		domain:   variable.domain.Copy(),
+		domain:   variable.domain,
+		size:     variable.domain.size,
+		assigned: variable.assigned,
 	}
 
 	t.stack = append(t.stack, entry)
@@ -57,22 +93,35 @@ func (t *Trail) Undo() {
 	targetSize := t.markers[len(t.markers) - 1]
 	t.markers   = t.markers[:len(t.markers) - 1]
 
+	t.reasons     = t.reasons[:t.reasonMarks[len(t.reasonMarks) - 1]]
+	t.reasonMarks = t.reasonMarks[:len(t.reasonMarks) - 1]
+
 	for len(t.stack) > targetSize {
 		entry   := t.stack[len(t.stack) - 1]
 		t.stack  = t.stack[:len(t.stack) - 1]
 
 		entry.variable.domain = entry.domain
+		entry.variable.domain.size = entry.size
 		entry.variable.modified = false
-		entry.variable.Unassign()
+		entry.variable.assigned = entry.assigned
+		entry.variable.resized()
 	}
 
 	t.numUndoes++
 }
 
+// undo markers until only level of them are left
+func (t *Trail) UndoTo(level int) {
+	for len(t.markers) > level {
+		t.Undo()
+	}
+}
+
 func (t *Trail) Clear() {
 	t.stack   = []trailEntry{}
 	t.markers = []int{}
 
+	t.reasons, t.reasonMarks, t.lastWipeout = nil, nil, nil
+
 	t.numPushes, t.numUndoes = 0, 0
 }
-
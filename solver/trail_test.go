@@ -0,0 +1,75 @@
+package solver
+
+import "testing"
+
+func newBenchVariables(count, size int) []*Variable {
+	values := make([]int, size)
+	for index := range values {
+		values[index] = index + 1
+	}
+
+	variables := make([]*Variable, count)
+	for index := range variables {
+		variables[index] = NewVariable("", values, nil)
+	}
+
+	return variables
+}
+
+func TestTrailUndoRestoresDomains(t *testing.T) {
+	variables := newBenchVariables(3, 4)
+	trail     := NewTrail()
+
+	trail.PlaceMarker()
+	trail.Push(variables[0])
+	variables[0].AssignValue(3)
+
+	trail.Push(variables[1])
+	variables[1].domain.Remove(3)
+
+	trail.PlaceMarker()
+	trail.Push(variables[1])
+	variables[1].domain.Remove(1)
+	trail.Push(variables[2])
+	variables[2].AssignValue(4)
+
+	trail.Undo()
+	if variables[1].Size() != 3 || variables[1].domain.Contains(3) || !variables[1].domain.Contains(1) {
+		t.Errorf("expected {1 2 4} after inner undo, got %v", variables[1].Values())
+	}
+	if variables[2].Assigned() || variables[2].Size() != 4 {
+		t.Errorf("expected unassigned full domain after inner undo, got %v", variables[2])
+	}
+	if !variables[0].Assigned() || variables[0].Assignment() != 3 {
+		t.Errorf("outer assignment lost by inner undo: %v", variables[0])
+	}
+
+	trail.Undo()
+	for _, variable := range variables {
+		if variable.Assigned() || variable.Size() != 4 {
+			t.Errorf("expected fresh variable after outer undo, got %v %v", variable, variable.Values())
+		}
+	}
+}
+
+// forward-checking shaped workload: one assignment, many single value removals
+func BenchmarkTrailPushUndo(b *testing.B) {
+	variables := newBenchVariables(81, 9)
+	trail     := NewTrail()
+
+	b.ReportAllocs()
+	for range b.N {
+		for value := 1; value <= 9; value++ {
+			trail.PlaceMarker()
+
+			for _, variable := range variables {
+				trail.Push(variable)
+				variable.domain.Remove(value)
+			}
+		}
+
+		for range 9 {
+			trail.Undo()
+		}
+	}
+}
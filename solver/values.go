@@ -0,0 +1,105 @@
+package solver
+
+/*
+Maps symbolic values (colors, letters, enums...) onto the int values that
+domains and constraints work with, so models keep their own value type while
+the solver keeps its int fast path.
+
+The i-th registered value is encoded as i + 1, which keeps 0 free to mean
+"unassigned" just like it does for Sudoku.
+*/
+type ValueMap[T comparable] struct {
+	values  []T
+	indices map[T]int
+}
+
+// duplicates are ignored, order of first appearance decides the encoding
+func NewValueMap[T comparable](values ...T) *ValueMap[T] {
+	valueMap := &ValueMap[T]{
+		values:  []T{},
+		indices: make(map[T]int, len(values)),
+	}
+
+	for _, value := range values {
+		if _, seen := valueMap.indices[value]; seen { continue }
+
+		valueMap.values = append(valueMap.values, value)
+		valueMap.indices[value] = len(valueMap.values)
+	}
+
+	return valueMap
+}
+
+// Accessors
+
+func (m *ValueMap[T]) Len() int {
+	return len(m.values)
+}
+
+func (m *ValueMap[T]) Encode(value T) (int, bool) {
+	encoded, ok := m.indices[value]
+	return encoded, ok
+}
+
+func (m *ValueMap[T]) Decode(encoded int) (T, bool) {
+	if encoded < 1 || encoded > len(m.values) {
+		var zero T
+		return zero, false
+	}
+
+	return m.values[encoded - 1], true
+}
+
+// encoded domain for the given values, or for every registered value when none are given
+func (m *ValueMap[T]) Domain(values ...T) []int {
+	if len(values) == 0 {
+		domain := make([]int, len(m.values))
+		for index := range m.values {
+			domain[index] = index + 1
+		}
+
+		return domain
+	}
+
+	domain := make([]int, 0, len(values))
+	for _, value := range values {
+		if encoded, ok := m.indices[value]; ok {
+			domain = append(domain, encoded)
+		}
+	}
+
+	return domain
+}
+
+// decoded values left in the variable's domain
+func (m *ValueMap[T]) Values(variable *Variable) []T {
+	values := make([]T, 0, variable.Size())
+
+	for _, encoded := range variable.Values() {
+		if value, ok := m.Decode(encoded); ok {
+			values = append(values, value)
+		}
+	}
+
+	return values
+}
+
+// decoded assignment, ok is false while the variable is unassigned
+func (m *ValueMap[T]) Assignment(variable *Variable) (T, bool) {
+	return m.Decode(variable.Assignment())
+}
+
+// Constructors
+
+// variable whose domain is the given values (every registered value when none are given)
+func (m *ValueMap[T]) NewVariable(name string, meta any, values ...T) *Variable {
+	return NewVariable(name, m.Domain(values...), meta)
+}
+
+func (m *ValueMap[T]) AssignValue(variable *Variable, value T) bool {
+	encoded, ok := m.indices[value]
+	if !ok { return false }
+
+	variable.AssignValue(encoded)
+	return true
+}
@@ -0,0 +1,73 @@
+package solver_test
+
+import (
+	"testing"
+	"time"
+	"github.com/stretchr/testify/assert"
+	"sudoku-csp/solver"
+)
+
+type color string
+
+func TestValueMapEncoding(t *testing.T) {
+	colors := solver.NewValueMap[color]("red", "green", "blue", "red")
+
+	assert.Equal(t, 3, colors.Len())
+	assert.Equal(t, []int{1, 2, 3}, colors.Domain())
+	assert.Equal(t, []int{3, 1}, colors.Domain("blue", "red", "purple"))
+
+	encoded, ok := colors.Encode("green")
+	assert.True(t, ok)
+	assert.Equal(t, 2, encoded)
+
+	decoded, ok := colors.Decode(encoded)
+	assert.True(t, ok)
+	assert.Equal(t, color("green"), decoded)
+
+	_, ok = colors.Decode(0)
+	assert.False(t, ok)
+}
+
+// classic Australia map coloring with symbolic values
+func TestValueMapColoring(t *testing.T) {
+	colors  := solver.NewValueMap[color]("red", "green", "blue")
+	network := solver.NewNetwork()
+
+	regions := map[string]*solver.Variable{}
+	for _, name := range []string{"WA", "NT", "SA", "Q", "NSW", "V", "T"} {
+		regions[name] = colors.NewVariable(name, nil)
+		network.AddVariable(regions[name])
+	}
+
+	borders := [][2]string{
+		{"WA", "NT"}, {"WA", "SA"}, {"NT", "SA"}, {"NT", "Q"}, {"SA", "Q"},
+		{"SA", "NSW"}, {"SA", "V"}, {"Q", "NSW"}, {"NSW", "V"},
+	}
+	for _, border := range borders {
+		pair := []*solver.Variable{regions[border[0]], regions[border[1]]}
+		network.AddConstraint(solver.NewAllDiffConstraint(pair))
+	}
+
+	assert.True(t, colors.AssignValue(regions["SA"], "blue"))
+
+	bt := solver.NewBacktrackSolver(
+		network,
+		solver.NewTrail(),
+		solver.MRV{},
+		solver.DefaultValOrder{},
+		solver.ForwardChecking{},
+	)
+
+	assert.True(t, bt.Solve(time.Second))
+
+	for _, border := range borders {
+		left, _  := colors.Assignment(regions[border[0]])
+		right, _ := colors.Assignment(regions[border[1]])
+
+		assert.NotEqual(t, left, right, "%s and %s share a color", border[0], border[1])
+	}
+
+	southAustralia, ok := colors.Assignment(regions["SA"])
+	assert.True(t, ok)
+	assert.Equal(t, color("blue"), southAustralia)
+}
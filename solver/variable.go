@@ -4,25 +4,33 @@ import "fmt"
 
 /*
 Represents a CSP variable
+
+Name identifies the variable, Meta carries whatever the model built on top of
+the solver needs to map the variable back (e.g. board coordinates).
 */
 type Variable struct {
 	domain *Domain
 
-	Row   int
-	Col   int
-	Block int
+	Name string
+	Meta any
 
 	assigned   bool
 	modified   bool
 	changeable bool
+
+	// position in the network's size buckets, see buckets.go
+	buckets *sizeBuckets
+	bucket  int
+	index   int // position in network.variables, MRV breaks ties with it
+
+	outOfScope bool // left out of the component being searched, see components.go
 }
 
-func NewVariable(values []int, row, col, block int) *Variable {
+func NewVariable(name string, values []int, meta any) *Variable {
 	variable := &Variable{
 		domain:     NewDomain(values...),
-		Row:        row,
-		Col:        col,
-		Block:      block,
+		Name:       name,
+		Meta:       meta,
 		changeable: len(values) > 1,
 		assigned:   len(values) == 1,
 		modified:   len(values) == 1,
@@ -34,9 +42,8 @@ func NewVariable(values []int, row, col, block int) *Variable {
 func (v *Variable) Copy() *Variable {
 	variable := &Variable{
 		domain:     v.domain.Copy(),
-		Row:        v.Row,
-		Col:        v.Col,
-		Block:      v.Block,
+		Name:       v.Name,
+		Meta:       v.Meta,
 		assigned:   v.assigned,
 		modified:   v.modified,
 		changeable: v.changeable,
@@ -55,7 +62,7 @@ func (v *Variable) Size() int {
 
 // all values in domain
 func (v *Variable) Values() []int {
-	return v.domain.values
+	return v.domain.Values()
 }
 
 // assignment is remaining value in domain + variable set to assigned. otherwise, 0 representing unassigned variable
@@ -76,13 +83,19 @@ func (v *Variable) Assigned() bool {
 func (v *Variable) AssignValue(value int) {
 	if !v.changeable { return }
 
-	v.domain = NewDomain(value)
+	// values outside the domain get a fresh one, the Trail still holds the old
+	if !v.domain.Assign(value) {
+		v.domain = NewDomain(value)
+	}
+
 	v.assigned, v.modified = true, true
+	v.resized()
 }
 
 func (v *Variable) Unassign() {
 	if v.changeable {
 		v.assigned = false
+		v.resized()
 	}
 }
 
@@ -91,11 +104,19 @@ func (v *Variable) RemoveValueFromDomain(value int) {
 
 	if v.domain.Remove(value) {
 		v.modified = true
+		v.resized()
+	}
+}
+
+// keeps the network's size buckets in step, call after changing the domain size or assigned flag
+func (v *Variable) resized() {
+	if v.buckets != nil {
+		v.buckets.update(v)
 	}
 }
 
 
 func (v *Variable) String() string {
-	return fmt.Sprintf("Row: %d, Col: %d, Block: %d, value: %d", v.Row, v.Col, v.Block, v.Assignment())
+	return fmt.Sprintf("%s, value: %d", v.Name, v.Assignment())
 }
 
@@ -4,8 +4,32 @@ import (
 	"testing"
 	"fmt"
 	"github.com/stretchr/testify/assert"
+	"sudoku-csp/solver"
 )
 
 func TestEmptyVariable(t *testing.T) {
+	variable := solver.NewVariable("empty", []int{}, nil)
 
+	assert.Equal(t, 0, variable.Size())
+	assert.Equal(t, 0, variable.Assignment())
+	assert.False(t, variable.Assigned())
+}
+
+func TestNamedVariable(t *testing.T) {
+	type color struct{ name string }
+
+	variable := solver.NewVariable("WA", []int{1, 2, 3}, color{"red"})
+	t.Log(fmt.Sprintf("\n%v\n", variable))
+
+	assert.Equal(t, "WA", variable.Name)
+	assert.Equal(t, color{"red"}, variable.Meta)
+	assert.False(t, variable.Assigned())
+
+	variable.AssignValue(2)
+	assert.True(t, variable.Assigned())
+	assert.Equal(t, 2, variable.Assignment())
+
+	copied := variable.Copy()
+	assert.Equal(t, variable.Name, copied.Name)
+	assert.Equal(t, variable.Meta, copied.Meta)
 }
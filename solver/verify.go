@@ -0,0 +1,48 @@
+package solver
+
+import "fmt"
+
+type ViolationKind int
+
+const (
+	Unassigned ViolationKind = iota
+	Unsatisfied
+)
+
+// one way an assignment fails to be a solution
+type Violation struct {
+	Kind       ViolationKind
+	Variable   *Variable  // set for Unassigned
+	Constraint Constraint // set for Unsatisfied
+}
+
+func (v Violation) String() string {
+	if v.Kind == Unassigned {
+		return fmt.Sprintf("unassigned: %s", v.Variable.Name)
+	}
+
+	return fmt.Sprintf("unsatisfied: %s", v.Constraint)
+}
+
+/*
+Checks that every variable holds exactly one value and every hard constraint is
+satisfied by it, without looking at any solver's state (HasSolution, trail,
+IsConsistent). No violations means the network holds a solution.
+*/
+func Verify(network *Network) []Violation {
+	violations := []Violation{}
+
+	for _, variable := range network.variables {
+		if !variable.assigned || variable.Size() != 1 {
+			violations = append(violations, Violation{Kind: Unassigned, Variable: variable})
+		}
+	}
+
+	for _, constraint := range network.constraints {
+		if !constraint.IsSatisfied() {
+			violations = append(violations, Violation{Kind: Unsatisfied, Constraint: constraint})
+		}
+	}
+
+	return violations
+}
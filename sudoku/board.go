@@ -7,6 +7,7 @@ import (
 	"math/rand/v2"
 	// "time"
 	"fmt"
+	"sudoku-csp/solver"
 )
 
 // Represents a Sudoku board
@@ -15,6 +16,10 @@ type Board struct {
 	BoxCols  int
 	boardLen int
 	Cells    [][]int
+
+	// seed of the generator that produced the board, pass it to the matching
+	// ...WithSeed constructor to get the same board back
+	Seed uint64
 }
 
 func NewEmptyBoard(boxRows, boxCols int) *Board {
@@ -34,6 +39,14 @@ func NewEmptyBoard(boxRows, boxCols int) *Board {
 }
 
 func NewRandomBoard(boxRows, boxCols, numHints int) *Board {
+	return NewRandomBoardWithSeed(solver.RandomSeed(), boxRows, boxCols, numHints)
+}
+
+func NewRandomBoardWithSeed(seed uint64, boxRows, boxCols, numHints int) *Board {
+	return newRandomBoard(solver.NewRand(seed), seed, boxRows, boxCols, numHints)
+}
+
+func newRandomBoard(rng *rand.Rand, seed uint64, boxRows, boxCols, numHints int) *Board {
 	if numHints > (boxRows * boxRows * boxCols * boxCols) {
 		numHints = (boxRows * boxRows * boxCols * boxCols)
 	}
@@ -45,6 +58,7 @@ func NewRandomBoard(boxRows, boxCols, numHints int) *Board {
 		BoxCols:  boxCols,
 		boardLen: boardLen,
 		Cells:    make([][]int, boardLen),
+		Seed:     seed,
 	}
 	
 	// initalize board matrix
@@ -52,12 +66,10 @@ func NewRandomBoard(boxRows, boxCols, numHints int) *Board {
 		board.Cells[row] = make([]int, boardLen)
 	}
 
-	// go 1.24 -> seed automatically set
-
 	// fill board w/ randomly placed hints
 	for numHints > 0 {
-		row, col := rand.IntN(boardLen), rand.IntN(boardLen)
-		value := rand.IntN(boardLen + 1)
+		row, col := rng.IntN(boardLen), rng.IntN(boardLen)
+		value := rng.IntN(boardLen + 1)
 
 		if board.Cells[row][col] == 0 && board.isValidPlacement(row, col, value) {
 			board.Cells[row][col] = value
@@ -0,0 +1,42 @@
+package sudoku
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestGeneratorsReplayFromSeed(t *testing.T) {
+	generators := map[string]func(seed uint64) *Board{
+		"random": func(seed uint64) *Board {
+			return NewRandomBoardWithSeed(seed, 2, 3, 12)
+		},
+		"forward-check": func(seed uint64) *Board {
+			return NewForwardCheckGeneratedBoardWithSeed(seed, 2, 3, 12)
+		},
+		"from-solved": func(seed uint64) *Board {
+			return NewBoardFromSolvedWithSeed(seed, 2, 3, 12)
+		},
+	}
+
+	for name, generate := range generators {
+		t.Run(name, func(t *testing.T) {
+			first  := generate(42)
+			replay := generate(first.Seed)
+
+			if first.Seed != 42 {
+				t.Errorf("expected seed 42 recorded, got %d", first.Seed)
+			}
+			if !reflect.DeepEqual(first.Cells, replay.Cells) {
+				t.Errorf("replay differs:\n%v\nvs\n%v", first, replay)
+			}
+
+			differs := false
+			for seed := uint64(1); seed <= 5 && !differs; seed++ {
+				differs = !reflect.DeepEqual(first.Cells, generate(seed).Cells)
+			}
+			if !differs {
+				t.Error("different seeds keep producing the same board")
+			}
+		})
+	}
+}
@@ -2,7 +2,6 @@ package sudoku
 
 import (
 	"sudoku-csp/solver"
-	"math/rand/v2"
 	"time"
 	"fmt"
 )
@@ -38,7 +37,8 @@ func NewNetworkFromBoard(board *Board) *solver.Network {
 
 			// boxIndex := (col / board.BoxCols) * board.BoxRows + (row / board.BoxRows)
 			// fmt.Printf("(%d, %d) assigned box %d\n\n", row, col, boxIndex)
-			variable := solver.NewVariable(domain, row, col, boxIndex)
+			cell     := Cell{Row: row, Col: col, Box: boxIndex}
+			variable := solver.NewVariable(cell.Name(), domain, cell)
 
 			tempVars = append(tempVars, variable)
 			network.AddVariable(variable)
@@ -51,9 +51,11 @@ func NewNetworkFromBoard(board *Board) *solver.Network {
 	boxGroups := make(map[int][]*solver.Variable)
 
 	for _, variable := range tempVars {
-		rowGroups[variable.Row] = append(rowGroups[variable.Row], variable)
-		colGroups[variable.Col] = append(colGroups[variable.Col], variable)
-		boxGroups[variable.Block] = append(boxGroups[variable.Block], variable)
+		cell, _ := CellOf(variable)
+
+		rowGroups[cell.Row] = append(rowGroups[cell.Row], variable)
+		colGroups[cell.Col] = append(colGroups[cell.Col], variable)
+		boxGroups[cell.Box] = append(boxGroups[cell.Box], variable)
 	}
 
 	// fmt.Printf("vars in row: %d, vars in col: %d, vars in box: %d\n", len(rowGroups), len(colGroups), len(boxGroups))
@@ -63,10 +65,10 @@ func NewNetworkFromBoard(board *Board) *solver.Network {
 	// 	fmt.Printf("Box %d has %d vars\n", i, len(boxGroups[i]))
 	// }
 
-	// 3) assign constraints for rows, cols, & boxes
+	// 3) assign constraints for rows, cols, & boxes (in index order, keeps solving reproducible)
 	for _, group := range []map[int][]*solver.Variable{rowGroups, colGroups, boxGroups} {
-		for _, vars := range group {
-			constraint := MakeAllDiff(vars)
+		for index := range boardLen {
+			constraint := MakeAllDiff(group[index])
 			network.AddConstraint(constraint)
 		}
 	}
@@ -84,9 +86,10 @@ func NewBoardFromNetwork(network *solver.Network, boxRows, boxCols int) *Board {
 	}
 
 	for _, variable := range network.Variables() {
-		if !variable.Assigned() { continue }
+		cell, ok := CellOf(variable)
+		if !ok || !variable.Assigned() { continue }
 
-		cells[variable.Row][variable.Col] = variable.Assignment()
+		cells[cell.Row][cell.Col] = variable.Assignment()
 	}
 
 	return &Board{
@@ -98,6 +101,11 @@ func NewBoardFromNetwork(network *solver.Network, boxRows, boxCols int) *Board {
 }
 
 func NewForwardCheckGeneratedBoard(boxRows, boxCols, numHints int) *Board {
+	return NewForwardCheckGeneratedBoardWithSeed(solver.RandomSeed(), boxRows, boxCols, numHints)
+}
+
+func NewForwardCheckGeneratedBoardWithSeed(seed uint64, boxRows, boxCols, numHints int) *Board {
+	rng     := solver.NewRand(seed)
 	board   := NewEmptyBoard(boxRows, boxCols)
 	network := NewNetworkFromBoard(board)
 	trail   := solver.NewTrail()
@@ -113,7 +121,7 @@ func NewForwardCheckGeneratedBoard(boxRows, boxCols, numHints int) *Board {
 		if variable == nil { break }
 
 		values := valSelector.OrderValues(variable, network)
-		rand.Shuffle(len(values), func(left, right int) {
+		rng.Shuffle(len(values), func(left, right int) {
 			values[left], values[right] = values[right], values[left]
 		})
 
@@ -137,10 +145,18 @@ func NewForwardCheckGeneratedBoard(boxRows, boxCols, numHints int) *Board {
 		if !placedValue { break }
 	}
 
-	return NewBoardFromNetwork(network, boxRows, boxCols)
+	board = NewBoardFromNetwork(network, boxRows, boxCols)
+	board.Seed = seed
+
+	return board
 }
 
 func NewBoardFromSolved(boxRows, boxCols, numHints int) *Board {
+	return NewBoardFromSolvedWithSeed(solver.RandomSeed(), boxRows, boxCols, numHints)
+}
+
+func NewBoardFromSolvedWithSeed(seed uint64, boxRows, boxCols, numHints int) *Board {
+	rng        := solver.NewRand(seed)
 	totalCells := boxRows * boxCols * boxRows * boxCols
 	
 	// fix later
@@ -152,8 +168,9 @@ func NewBoardFromSolved(boxRows, boxCols, numHints int) *Board {
 	network := NewNetworkFromBoard(board)
 	trail   := solver.NewTrail()
 	
+	// shuffled value order, otherwise every seed would solve to the same grid
 	varSelector := solver.MRV{}
-	valSelector := solver.LeastConstrainingValue{}
+	valSelector := solver.NewRandomValOrder(rng.Uint64(), nil)
 	checker     := solver.ForwardChecking{}
 
 	solver := solver.NewBacktrackSolver(network, trail, varSelector, valSelector, checker)
@@ -163,7 +180,7 @@ func NewBoardFromSolved(boxRows, boxCols, numHints int) *Board {
 	fmt.Printf("[ DEBUG ] the solved board:\n%v\n", board)
 	
 	positions := allCellCoords(board.BoardLen())
-	rand.Shuffle(len(positions), func(left, right int) {
+	rng.Shuffle(len(positions), func(left, right int) {
 		positions[left], positions[right] = positions[right], positions[left]
 	})
 
@@ -172,6 +189,7 @@ func NewBoardFromSolved(boxRows, boxCols, numHints int) *Board {
 		board.Cells[row][col] = 0
 	}
 
+	board.Seed = seed
 	return board
 }
 
@@ -202,3 +220,30 @@ func allCellCoords(dimension int) [][]int {
 	return pairs
 }
 
+
+/*
+Runs checker once over the givens, without any search, ok when that narrows
+every cell down to a single value. Used to tell "no-guess" puzzles apart, e.g.
+with solver.SAC as the checker.
+*/
+func SolveWithoutGuessing(board *Board, checker solver.ConsistencyChecker) (*Board, bool) {
+	network := NewNetworkFromBoard(board)
+	if !checker.Enforce(network, solver.NewTrail()) {
+		return nil, false
+	}
+
+	solved := NewEmptyBoard(board.BoxRows, board.BoxCols)
+	solvedAll := true
+
+	for _, variable := range network.Variables() {
+		cell, _ := CellOf(variable)
+
+		if variable.Size() == 1 {
+			solved.Cells[cell.Row][cell.Col] = variable.Values()[0]
+		} else {
+			solvedAll = false
+		}
+	}
+
+	return solved, solvedAll
+}
@@ -0,0 +1,164 @@
+package sudoku
+
+import (
+	"testing"
+	"time"
+	"sudoku-csp/solver"
+)
+
+func benchmarkSolveEmpty(b *testing.B, boxRows, boxCols int) {
+	b.ReportAllocs()
+
+	for range b.N {
+		network := NewNetworkFromBoard(NewEmptyBoard(boxRows, boxCols))
+		bt := solver.NewBacktrackSolver(
+			network,
+			solver.NewTrail(),
+			solver.MRV{},
+			solver.DefaultValOrder{},
+			solver.ForwardChecking{},
+		)
+
+		if !bt.Solve(time.Minute) {
+			b.Fatal("empty board not solved")
+		}
+	}
+}
+
+func BenchmarkSolveEmpty9x9(b *testing.B)   { benchmarkSolveEmpty(b, 3, 3) }
+func BenchmarkSolveEmpty16x16(b *testing.B) { benchmarkSolveEmpty(b, 4, 4) }
+func BenchmarkSolveEmpty25x25(b *testing.B) { benchmarkSolveEmpty(b, 5, 5) }
+
+// MRV picks from a smallest bucket holding every cell but the last one pruned
+func BenchmarkMRVSelectEmpty25x25(b *testing.B) {
+	network   := NewNetworkFromBoard(NewEmptyBoard(5, 5))
+	trail     := solver.NewTrail()
+	variables := network.Variables()
+	last      := variables[len(variables) - 1]
+
+	network.Finalize()
+	b.ResetTimer()
+
+	for range b.N {
+		trail.PlaceMarker()
+		trail.Push(last)
+		last.RemoveValueFromDomain(1)
+
+		if (solver.MRV{}).Select(network) != last { b.Fatal("pruned cell not selected") }
+
+		trail.Undo()
+		if (solver.MRV{}).Select(network) != variables[0] { b.Fatal("first cell not selected") }
+	}
+}
+
+// rows of digits, '.' or '0' for blanks
+func boardFromRows(boxRows, boxCols int, rows ...string) *Board {
+	board := NewEmptyBoard(boxRows, boxCols)
+
+	for row, line := range rows {
+		for col, char := range line {
+			if char >= '1' && char <= '9' {
+				board.Cells[row][col] = int(char - '0')
+			}
+		}
+	}
+
+	return board
+}
+
+func puzzle9x9() *Board {
+	return boardFromRows(3, 3,
+		"53..7....",
+		"6..195...",
+		".98....6.",
+		"8...6...3",
+		"4..8.3..1",
+		"7...2...6",
+		".6....28.",
+		"...419..5",
+		"....8..79",
+	)
+}
+
+func assertSolved(t *testing.T, original, solved *Board) {
+	t.Helper()
+
+	if violations := solved.VerifySolution(original); len(violations) > 0 {
+		t.Fatalf("not a solution: %v\n%v", violations, solved)
+	}
+}
+
+func TestCheckersSolvePuzzle(t *testing.T) {
+	checkers := map[string]solver.ConsistencyChecker{
+		"basic":   solver.BasicCheck{},
+		"forward": solver.ForwardChecking{},
+		"norvig":  solver.NorvigCheck{},
+		"arc":     solver.ArcConsistency{},
+		"gac":     solver.AllDiffGAC{},
+		"pipeline": solver.NewPipeline(
+			solver.ForwardChecking{},
+			solver.HiddenSingles{},
+			solver.AllDiffGAC{},
+		),
+	}
+
+	for name, checker := range checkers {
+		t.Run(name, func(t *testing.T) {
+			board   := puzzle9x9()
+			network := NewNetworkFromBoard(board)
+
+			bt := solver.NewBacktrackSolver(network, solver.NewTrail(), solver.MRV{}, solver.DefaultValOrder{}, checker)
+			if !bt.Solve(time.Minute) {
+				t.Fatal("puzzle not solved")
+			}
+
+			assertSolved(t, board, NewBoardFromNetwork(network, 3, 3))
+		})
+	}
+}
+
+func TestSACSolvesWithoutGuessing(t *testing.T) {
+	board := puzzle9x9()
+
+	solved, ok := SolveWithoutGuessing(board, solver.SAC{})
+	if !ok {
+		t.Fatalf("SAC left cells open:\n%v", solved)
+	}
+	assertSolved(t, board, solved)
+
+	// an empty board needs guessing whatever the checker
+	if _, ok := SolveWithoutGuessing(NewEmptyBoard(2, 2), solver.SAC{}); ok {
+		t.Error("empty board reported as solved without guessing")
+	}
+}
+
+func TestSolvableWithAssumptions(t *testing.T) {
+	network := NewNetworkFromBoard(puzzle9x9())
+	bt := solver.NewBacktrackSolver(network, solver.NewTrail(), solver.MRV{}, solver.DefaultValOrder{}, solver.ForwardChecking{})
+
+	target := VariableAt(network, 0, 2)
+
+	// the puzzle has a unique solution with a 4 in row 0, col 2
+	for value := 1; value <= 9; value++ {
+		solvable := bt.SolvableWith(time.Minute, solver.Assumption{Variable: target, Value: value})
+		if solvable != (value == 4) {
+			t.Errorf("r0c2 = %d: solvable %v", value, solvable)
+		}
+
+		if target.Assigned() || bt.Depth() != 0 {
+			t.Fatalf("assumption r0c2 = %d leaked into the network", value)
+		}
+	}
+
+	// constraints are retracted with their level too
+	bt.Push()
+	if bt.AssumeConstraint(solver.NewEqualsConstraint(target, 5)) && bt.Solve(time.Minute) {
+		t.Error("solved with r0c2 forced to 5")
+	}
+	bt.Pop()
+
+	if !bt.Solve(time.Minute) {
+		t.Fatal("puzzle not solvable after popping assumptions")
+	}
+	assertSolved(t, puzzle9x9(), NewBoardFromNetwork(network, 3, 3))
+}
@@ -0,0 +1,46 @@
+package sudoku
+
+import (
+	"fmt"
+	"sudoku-csp/solver"
+)
+
+// Board coordinates of a cell, stored as the Meta of its solver.Variable
+type Cell struct {
+	Row int
+	Col int
+	Box int
+}
+
+func (c Cell) Name() string {
+	return fmt.Sprintf("r%dc%d", c.Row, c.Col)
+}
+
+func (c Cell) String() string {
+	return fmt.Sprintf("Row: %d, Col: %d, Box: %d", c.Row, c.Col, c.Box)
+}
+
+// cell coordinates of a variable built by NewNetworkFromBoard
+func CellOf(variable *solver.Variable) (Cell, bool) {
+	cell, ok := variable.Meta.(Cell)
+	return cell, ok
+}
+
+// variable of the cell at (row, col) in a network built by NewNetworkFromBoard, nil if there is none
+func VariableAt(network *solver.Network, row, col int) *solver.Variable {
+	for _, variable := range network.Variables() {
+		if cell, ok := CellOf(variable); ok && cell.Row == row && cell.Col == col {
+			return variable
+		}
+	}
+
+	return nil
+}
+
+// why the cell at (row, col) can't be value, trail needs explanations enabled before propagating
+func WhyNot(network *solver.Network, trail *solver.Trail, row, col, value int) *solver.Explanation {
+	variable := VariableAt(network, row, col)
+	if variable == nil { return nil }
+
+	return trail.Why(variable, value)
+}
@@ -0,0 +1,72 @@
+package sudoku
+
+import (
+	"strings"
+	"testing"
+	"sudoku-csp/solver"
+)
+
+func TestWhyNot(t *testing.T) {
+	network := NewNetworkFromBoard(puzzle9x9())
+	trail   := solver.NewTrail()
+	trail.EnableExplanations()
+
+	if !(solver.NorvigCheck{}).Enforce(network, trail) {
+		t.Fatal("propagation failed on a valid puzzle")
+	}
+
+	// r0c0 is a given 5
+	why := WhyNot(network, trail, 0, 2, 5)
+	if why.Reason == nil || len(why.Because) != 1 {
+		t.Fatalf("expected a single cause, got:\n%v", why)
+	}
+	if cause := why.Because[0].Fact; cause.Variable != VariableAt(network, 0, 0) || cause.Value != 5 || !cause.Assigned {
+		t.Errorf("expected r0c0 = 5 as the cause, got %v", cause)
+	}
+	if !strings.Contains(why.String(), "[given]") {
+		t.Errorf("expected the chain to end in a given:\n%v", why)
+	}
+
+	// hidden singles chain back through other derived cells
+	deepest := 0
+	for _, variable := range network.Variables() {
+		cell, _ := CellOf(variable)
+		if puzzle9x9().Cells[cell.Row][cell.Col] != 0 { continue }
+
+		other := variable.Assignment() % 9 + 1
+		deepest = max(deepest, depthOf(WhyNot(network, trail, cell.Row, cell.Col, other)))
+	}
+	if deepest < 3 {
+		t.Errorf("expected some multi-step justification, deepest chain was %d", deepest)
+	}
+}
+
+func depthOf(explanation *solver.Explanation) int {
+	deepest := 0
+	for _, cause := range explanation.Because {
+		deepest = max(deepest, depthOf(cause))
+	}
+
+	return deepest + 1
+}
+
+func TestLastWipeout(t *testing.T) {
+	network := NewNetworkFromBoard(puzzle9x9())
+	trail   := solver.NewTrail()
+	trail.EnableExplanations()
+
+	bt := solver.NewBacktrackSolver(network, trail, solver.MRV{}, solver.DefaultValOrder{}, solver.ForwardChecking{})
+
+	// r0c2 can't be 3, r0c1 already is
+	bt.Push()
+	if bt.Assume(VariableAt(network, 0, 2), 3) {
+		t.Fatal("conflicting assumption accepted")
+	}
+	bt.Pop()
+
+	wipeout := trail.LastWipeout()
+	if wipeout == nil || !wipeout.Wipeout || wipeout.Fact.Variable != VariableAt(network, 0, 2) {
+		t.Fatalf("expected r0c2 wipeout, got %v", wipeout)
+	}
+	t.Logf("\n%v", trail.LastWipeout())
+}
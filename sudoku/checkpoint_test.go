@@ -0,0 +1,151 @@
+package sudoku
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+	"sudoku-csp/solver"
+)
+
+func TestCheckpointResumesCount(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "count.json")
+
+	bt := newTestSolver(NewEmptyBoard(2, 2))
+	bt.Limits.Nodes = 200
+
+	search := solver.NewSearch(bt)
+	for search.Run(time.Minute) == solver.Solved {}
+
+	if search.Result() != solver.Stopped {
+		t.Fatalf("expected the node limit to stop the count, got %v", search.Result())
+	}
+	if err := search.Checkpoint().Save(path); err != nil {
+		t.Fatal(err)
+	}
+
+	// as if in a new process
+	checkpoint, err := solver.LoadCheckpoint(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	resumedBt := newTestSolver(NewEmptyBoard(2, 2))
+	resumed, err := solver.ResumeSearch(resumedBt, checkpoint)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(resumed.Path()) != len(search.Path()) || resumedBt.Stats.Solutions != bt.Stats.Solutions {
+		t.Fatalf("resumed at depth %d with %d solutions, checkpointed at %d with %d",
+			len(resumed.Path()), resumedBt.Stats.Solutions, len(search.Path()), bt.Stats.Solutions)
+	}
+
+	for resumed.Run(time.Minute) == solver.Solved {}
+
+	if resumed.Result() != solver.Exhausted || resumedBt.Stats.Solutions != 288 {
+		t.Errorf("expected 288 grids across both runs, got %d (%v)", resumedBt.Stats.Solutions, resumedBt.Stats)
+	}
+}
+
+func TestCheckpointRejectsMismatch(t *testing.T) {
+	search := solver.NewSearch(newTestSolver(NewEmptyBoard(2, 2)))
+	search.Step()
+
+	checkpoint := search.Checkpoint()
+
+	if _, err := solver.ResumeSearch(newTestSolver(NewEmptyBoard(3, 3)), checkpoint); err == nil {
+		t.Error("resumed a 4x4 checkpoint into a 9x9 network")
+	}
+
+	// hand-edited frames get an error instead of a panic
+	frame := &checkpoint.Frames[0]
+	saved := *frame
+	for _, malformed := range []solver.CheckpointFrame{
+		{Variable: saved.Variable, Values: saved.Values, Next: 0, Open: true},
+		{Variable: saved.Variable, Values: saved.Values, Next: -1},
+		{Variable: saved.Variable, Values: []int{saved.Values[0], 7}, Next: 1, Open: true},
+		{Variable: len(checkpoint.Variables), Values: saved.Values, Next: 1, Open: true},
+	} {
+		*frame = malformed
+		if _, err := solver.ResumeSearch(newTestSolver(NewEmptyBoard(2, 2)), checkpoint); err == nil {
+			t.Errorf("resumed a checkpoint with frame %+v", malformed)
+		}
+	}
+	*frame = saved
+
+	checkpoint.Version++
+	if _, err := solver.ResumeSearch(newTestSolver(NewEmptyBoard(2, 2)), checkpoint); err == nil {
+		t.Error("resumed a checkpoint of another version")
+	}
+
+	path := filepath.Join(t.TempDir(), "future.json")
+	if err := checkpoint.Save(path); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := solver.LoadCheckpoint(path); err == nil || !strings.Contains(err.Error(), "version") {
+		t.Errorf("expected a version error loading, got %v", err)
+	}
+}
+
+func TestFailedResumeLeavesNetworkAlone(t *testing.T) {
+	search := solver.NewSearch(newTestSolver(NewEmptyBoard(2, 2)))
+	for search.Depth() < 3 {
+		search.Step()
+	}
+
+	// the third frame can't replay, after the first two have
+	checkpoint := search.Checkpoint()
+	checkpoint.Frames[2].Values = []int{9}
+
+	bt := newTestSolver(NewEmptyBoard(2, 2))
+	cell := VariableAt(bt.Network, 3, 3)
+	bt.Trail.PlaceMarker()
+	bt.Trail.Push(cell)
+	cell.RemoveValueFromDomain(2)
+
+	type state struct {
+		values   []int
+		assigned bool
+	}
+	states := func() []state {
+		saved := []state{}
+		for _, variable := range bt.Network.Variables() {
+			values := slices.Sorted(slices.Values(variable.Values()))
+			saved = append(saved, state{values, variable.Assigned()})
+		}
+		return saved
+	}
+
+	before := states()
+	if _, err := solver.ResumeSearch(bt, checkpoint); err == nil {
+		t.Fatal("resumed a checkpoint with a frame outside the domain")
+	}
+
+	if !reflect.DeepEqual(before, states()) || bt.Trail.Level() != 1 {
+		t.Errorf("failed resume changed the network, trail at level %d", bt.Trail.Level())
+	}
+
+	// and the trail still undoes what was there before
+	bt.Trail.Undo()
+	if cell.Size() != 4 {
+		t.Errorf("expected the removed value back, got %v", cell.Values())
+	}
+}
+
+func TestCheckpointEvery(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "periodic.json")
+
+	bt := newTestSolver(NewEmptyBoard(2, 2))
+	search := solver.NewSearch(bt)
+	search.CheckpointEvery(path, time.Nanosecond)
+
+	for search.Run(time.Minute) == solver.Solved {}
+
+	if _, err := os.Stat(path); err != nil || search.CheckpointError() != nil {
+		t.Errorf("expected a periodic checkpoint file: %v, %v", err, search.CheckpointError())
+	}
+}
@@ -0,0 +1,67 @@
+package sudoku
+
+import (
+	"time"
+	"sudoku-csp/solver"
+)
+
+/*
+Closest valid grid to a possibly broken board
+
+Every filled cell turns into a soft preference instead of a given, and a Max-CSP
+solve looks for the full grid that keeps as many of them as possible. Returns
+that grid, how many filled cells it had to change, and whether that number is
+proven minimal (false when timeLimit cut the search short).
+*/
+func ClosestValidBoard(board *Board, timeLimit time.Duration) (*Board, int, bool) {
+	network   := NewNetworkFromBoard(NewEmptyBoard(board.BoxRows, board.BoxCols))
+	preferred := map[*solver.Variable]int{}
+
+	for _, variable := range network.Variables() {
+		cell, _ := CellOf(variable)
+
+		value := board.Cells[cell.Row][cell.Col]
+		if value == 0 { continue }
+
+		preferred[variable] = value
+		network.AddSoftConstraint(solver.NewEqualsConstraint(variable, value), 1)
+	}
+
+	bt := solver.NewBacktrackSolver(
+		network,
+		solver.NewTrail(),
+		solver.MRV{},
+		preferEntries{preferred, solver.DefaultValOrder{}},
+		solver.ForwardChecking{},
+	)
+
+	maxCSP := solver.NewMaxCSPSolver(bt)
+	if !maxCSP.Optimize(timeLimit) {
+		return nil, 0, false
+	}
+
+	return NewBoardFromNetwork(network, board.BoxRows, board.BoxCols), maxCSP.BestValue, maxCSP.Optimal
+}
+
+// tries the user's entry for a cell before anything else, so good grids turn up early
+type preferEntries struct {
+	preferred map[*solver.Variable]int
+	inner     solver.ValSelector
+}
+
+func (p preferEntries) OrderValues(variable *solver.Variable, network *solver.Network) []int {
+	values := p.inner.OrderValues(variable, network)
+
+	want, ok := p.preferred[variable]
+	if !ok { return values }
+
+	for index, value := range values {
+		if value == want {
+			copy(values[1:index + 1], values[:index])
+			values[0] = want
+			break
+		}
+	}
+
+	return values
+}
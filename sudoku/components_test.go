@@ -0,0 +1,35 @@
+package sudoku
+
+import (
+	"testing"
+	"time"
+)
+
+func TestCountByComponentsMatchesEnumeration(t *testing.T) {
+	// two unavoidable rectangles, one per band, sharing no row, column or box:
+	//   1 2 | 3 4      . 2 | . 4
+	//   3 4 | 1 2  ->  . 4 | . 2
+	//   2 1 | 4 3      2 . | 4 .
+	//   4 3 | 2 1      4 . | 2 .
+	board := boardFromRows(2, 2,
+		".2.4",
+		".4.2",
+		"2.4.",
+		"4.2.",
+	)
+
+	bt := newTestSolver(board)
+	if components := bt.Network.Components(); len(components) != 2 {
+		t.Fatalf("expected the givens to split the board in 2, got %d components", len(components))
+	}
+
+	count, ok := bt.CountByComponents(time.Minute)
+	if !ok {
+		t.Fatalf("count stopped early: %v", bt.Stats)
+	}
+
+	enumerated := newTestSolver(board).SolveAll(time.Minute, nil)
+	if enumerated != 4 || count.Int64() != int64(enumerated) {
+		t.Errorf("components counted %v grids, enumeration found %d", count, enumerated)
+	}
+}
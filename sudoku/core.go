@@ -0,0 +1,38 @@
+package sudoku
+
+import (
+	"time"
+	"sudoku-csp/solver"
+)
+
+/*
+Givens of a board with no solution that already conflict among themselves:
+removing any one of them (when minimal is true) leaves the rest solvable.
+Returns false when the board does have a solution or timeLimit ran out first.
+*/
+func ConflictingGivens(board *Board, timeLimit time.Duration) (givens []Cell, minimal bool, ok bool) {
+	network := NewNetworkFromBoard(NewEmptyBoard(board.BoxRows, board.BoxCols))
+
+	assumptions := []solver.Assumption{}
+	for _, variable := range network.Variables() {
+		cell, _ := CellOf(variable)
+
+		if value := board.Cells[cell.Row][cell.Col]; value != 0 {
+			assumptions = append(assumptions, solver.Assumption{Variable: variable, Value: value})
+		}
+	}
+
+	bt := solver.NewBacktrackSolver(network, solver.NewTrail(), solver.MRV{}, solver.DefaultValOrder{}, solver.ForwardChecking{})
+
+	core, ok := bt.MinimalCore(timeLimit, assumptions)
+	if !ok {
+		return nil, false, false
+	}
+
+	for _, assumption := range core.Assumptions {
+		cell, _ := CellOf(assumption.Variable)
+		givens = append(givens, cell)
+	}
+
+	return givens, core.Minimal, true
+}
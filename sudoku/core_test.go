@@ -0,0 +1,34 @@
+package sudoku
+
+import (
+	"testing"
+	"time"
+)
+
+func TestConflictingGivens(t *testing.T) {
+	// the two 1s in row 0 clash, the other givens are innocent bystanders
+	board := boardFromRows(2, 2,
+		"1..1",
+		"....",
+		".3..",
+		"...2",
+	)
+
+	givens, minimal, ok := ConflictingGivens(board, time.Minute)
+	if !ok {
+		t.Fatal("board reported as solvable")
+	}
+	if !minimal {
+		t.Error("core not proven minimal")
+	}
+
+	expected := []Cell{{Row: 0, Col: 0, Box: 0}, {Row: 0, Col: 3, Box: 1}}
+	if len(givens) != len(expected) || givens[0] != expected[0] || givens[1] != expected[1] {
+		t.Errorf("expected core %v, got %v", expected, givens)
+	}
+
+	solvable := boardFromRows(2, 2, "12..", "....", "....", "....")
+	if _, _, ok := ConflictingGivens(solvable, time.Minute); ok {
+		t.Error("solvable board reported a conflict")
+	}
+}
@@ -0,0 +1,99 @@
+package sudoku
+
+import (
+	"testing"
+	"time"
+	"sudoku-csp/solver"
+)
+
+var discrepancyModes = []solver.SearchMode{solver.LimitedDiscrepancy, solver.DepthBoundedDiscrepancy}
+
+func TestDiscrepancySearchSolvesPuzzle(t *testing.T) {
+	for _, mode := range discrepancyModes {
+		t.Run(mode.String(), func(t *testing.T) {
+			board := puzzle9x9()
+
+			bt := solver.NewBacktrackSolver(
+				NewNetworkFromBoard(board),
+				solver.NewTrail(),
+				solver.MRV{},
+				solver.LeastConstrainingValue{},
+				solver.ForwardChecking{},
+			)
+			bt.Mode = mode
+
+			if !bt.Solve(time.Minute) {
+				t.Fatalf("puzzle not solved: %v", bt.Stats)
+			}
+
+			assertSolved(t, board, NewBoardFromNetwork(bt.Network, 3, 3))
+		})
+	}
+}
+
+func TestDiscrepancySearchReportsEachGridOnce(t *testing.T) {
+	for _, mode := range discrepancyModes {
+		t.Run(mode.String(), func(t *testing.T) {
+			bt := newTestSolver(NewEmptyBoard(2, 2))
+			bt.Mode = mode
+
+			seen  := map[string]bool{}
+			count := bt.SolveAll(time.Minute, func() bool {
+				grid := NewBoardFromNetwork(bt.Network, 2, 2).String()
+				if seen[grid] {
+					t.Fatalf("grid reported twice:\n%v", grid)
+				}
+				seen[grid] = true
+
+				return true
+			})
+
+			if count != 288 || bt.Stopped() {
+				t.Errorf("expected all 288 grids, got %d (%v)", count, bt.Stats)
+			}
+		})
+	}
+}
+
+func TestDiscrepancyLimit(t *testing.T) {
+	bt := newTestSolver(NewEmptyBoard(2, 2))
+	bt.Mode   = solver.LimitedDiscrepancy
+	bt.Limits = solver.Limits{Discrepancies: 1}
+
+	count := bt.SolveAll(time.Minute, nil)
+
+	if bt.Stats.Exhausted != solver.DiscrepancyLimit || count == 0 || count >= 288 {
+		t.Errorf("expected a partial enumeration stopped by the discrepancy limit, got %d (%v)", count, bt.Stats)
+	}
+}
+
+// LeastConstrainingValue ordering with and without trusting it
+func benchmarkGeneratedLCV(b *testing.B, mode solver.SearchMode) {
+	boards := make([]*Board, 8)
+	for index := range boards {
+		boards[index] = NewBoardFromSolvedWithSeed(uint64(index + 1), 3, 3, 25)
+	}
+
+	b.ResetTimer()
+
+	for range b.N {
+		for _, board := range boards {
+			bt := solver.NewBacktrackSolver(
+				NewNetworkFromBoard(board),
+				solver.NewTrail(),
+				solver.MRV{},
+				solver.LeastConstrainingValue{},
+				solver.ForwardChecking{},
+			)
+			bt.Mode = mode
+
+			if !bt.Solve(time.Minute) {
+				b.Fatalf("generated board not solved (seed %d)", board.Seed)
+			}
+		}
+	}
+}
+
+func BenchmarkGeneratedLCVDepthFirst(b *testing.B)   { benchmarkGeneratedLCV(b, solver.DepthFirst) }
+func BenchmarkGeneratedLCVDiscrepancy(b *testing.B)  { benchmarkGeneratedLCV(b, solver.LimitedDiscrepancy) }
+func BenchmarkGeneratedLCVDepthBounded(b *testing.B) { benchmarkGeneratedLCV(b, solver.DepthBoundedDiscrepancy) }
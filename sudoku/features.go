@@ -0,0 +1,8 @@
+package sudoku
+
+import "sudoku-csp/solver"
+
+// instance features of the board's network, for picking a solver configuration
+func FeaturesOf(board *Board) solver.Features {
+	return solver.ExtractFeatures(NewNetworkFromBoard(board))
+}
@@ -0,0 +1,38 @@
+package sudoku
+
+import (
+	"testing"
+	"time"
+	"sudoku-csp/solver"
+)
+
+func TestFeaturesLeaveNetworkUntouched(t *testing.T) {
+	board   := puzzle9x9()
+	network := NewNetworkFromBoard(board)
+
+	features := solver.ExtractFeatures(network)
+
+	if features.Variables != 81 || features.MaxDomain != 9 || features.Wipeout {
+		t.Fatalf("unexpected features: %v", features)
+	}
+	if features.Propagated < features.Givens || features.DomainSizes[9] != 81 - features.Givens {
+		t.Errorf("propagation should only add assignments: %v", features)
+	}
+
+	// givens still pending for the solver's first propagation
+	bt := solver.NewBacktrackSolver(network, solver.NewTrail(), solver.MRV{}, solver.DefaultValOrder{}, solver.ForwardChecking{})
+	if !bt.Solve(time.Minute) {
+		t.Fatal("puzzle not solved after extracting features")
+	}
+	assertSolved(t, board, NewBoardFromNetwork(network, 3, 3))
+}
+
+func TestDefaultRulesPickKnownConfigurations(t *testing.T) {
+	for _, shape := range [][2]int{{2, 2}, {3, 3}, {4, 4}, {5, 5}} {
+		features := FeaturesOf(NewEmptyBoard(shape[0], shape[1]))
+
+		if _, err := solver.DefaultRules.Configure(features); err != nil {
+			t.Errorf("%dx%d boxes: %v", shape[0], shape[1], err)
+		}
+	}
+}
@@ -0,0 +1,63 @@
+package sudoku
+
+import (
+	"testing"
+	"time"
+	"sudoku-csp/solver"
+)
+
+func newTestSolver(board *Board) *solver.BacktrackSolver {
+	return solver.New(NewNetworkFromBoard(board))
+}
+
+func TestCountAll4x4Grids(t *testing.T) {
+	bt := newTestSolver(NewEmptyBoard(2, 2))
+
+	count := bt.SolveAll(time.Minute, func() bool {
+		assertSolved(t, nil, NewBoardFromNetwork(bt.Network, 2, 2))
+		return true
+	})
+
+	if count != 288 || bt.Stopped() {
+		t.Errorf("expected all 288 grids, got %d (%v)", count, bt.Stats)
+	}
+
+	for _, variable := range bt.Network.Variables() {
+		if variable.Assigned() {
+			t.Fatal("finished enumeration left assignments behind")
+		}
+	}
+}
+
+func TestLimitsReported(t *testing.T) {
+	cases := map[string]struct {
+		limits   solver.Limits
+		expected solver.Limit
+	}{
+		"solutions":  {solver.Limits{Solutions: 5}, solver.SolutionLimit},
+		"nodes":      {solver.Limits{Nodes: 10}, solver.NodeLimit},
+		"trail size": {solver.Limits{TrailSize: 20}, solver.TrailLimit},
+		"time":       {solver.Limits{Time: time.Nanosecond}, solver.TimeLimit},
+	}
+
+	for name, test := range cases {
+		t.Run(name, func(t *testing.T) {
+			bt := newTestSolver(NewEmptyBoard(3, 3))
+			bt.Limits = test.limits
+
+			bt.SolveAll(time.Minute, nil)
+			if bt.Stats.Exhausted != test.expected {
+				t.Errorf("expected %v limit, got %v", test.expected, bt.Stats)
+			}
+		})
+	}
+
+	// backtracks only happen on a board that forces some
+	bt := newTestSolver(NewEmptyBoard(3, 3))
+	bt.ConsistencyChecker = solver.BasicCheck{}
+	bt.Limits = solver.Limits{Backtracks: 3}
+
+	if bt.Solve(time.Minute) || bt.Stats.Exhausted != solver.BacktrackLimit {
+		t.Errorf("expected backtrack limit, got %v", bt.Stats)
+	}
+}
@@ -0,0 +1,79 @@
+package sudoku
+
+import (
+	"testing"
+	"time"
+	"sudoku-csp/solver"
+)
+
+func TestLargestMainDiagonal(t *testing.T) {
+	board   := NewEmptyBoard(2, 2)
+	network := NewNetworkFromBoard(board)
+
+	diagonal := []*solver.Variable{}
+	for _, variable := range network.Variables() {
+		if cell, _ := CellOf(variable); cell.Row == cell.Col {
+			diagonal = append(diagonal, variable)
+		}
+	}
+
+	bt := solver.NewBacktrackSolver(network, solver.NewTrail(), solver.MRV{}, solver.DefaultValOrder{}, solver.ForwardChecking{})
+	optimizer := solver.NewOptimizingSolver(bt, solver.SumObjective(diagonal, 1), solver.Maximize)
+
+	improvements := []int{}
+	optimizer.OnImprove = func(value int, solution []int) {
+		improvements = append(improvements, value)
+	}
+
+	if !optimizer.Optimize(time.Minute) {
+		t.Fatal("no solution found")
+	}
+
+	// cells (0,0)/(1,1) and (2,2)/(3,3) share a box, so 4+3+4+3 is the best possible
+	if !optimizer.Optimal || optimizer.BestValue != 14 {
+		t.Errorf("expected proven optimum 14, got %d (optimal: %v)", optimizer.BestValue, optimizer.Optimal)
+	}
+
+	for index := 1; index < len(improvements); index++ {
+		if improvements[index] <= improvements[index - 1] {
+			t.Errorf("improvements not strictly increasing: %v", improvements)
+		}
+	}
+
+	solved := NewBoardFromNetwork(network, 2, 2)
+	assertSolved(t, board, solved)
+	t.Logf("\n%v", solved)
+}
+
+func TestClosestValidBoard(t *testing.T) {
+	broken := boardFromRows(2, 2,
+		"12..",
+		"3.1.",
+		"1...",
+		"....",
+	)
+
+	closest, changed, optimal := ClosestValidBoard(broken, time.Minute)
+	if closest == nil {
+		t.Fatal("no grid found")
+	}
+
+	// the two 1s in column 0 clash, dropping either one is enough
+	if changed != 1 || !optimal {
+		t.Errorf("expected a proven single change, got %d (optimal: %v)", changed, optimal)
+	}
+
+	kept := 0
+	for row := range broken.BoardLen() {
+		for col := range broken.BoardLen() {
+			if value := broken.Cells[row][col]; value != 0 && closest.Cells[row][col] == value {
+				kept++
+			}
+		}
+	}
+	if kept != 4 {
+		t.Errorf("expected 4 of 5 entries kept, kept %d:\n%v", kept, closest)
+	}
+
+	assertSolved(t, NewEmptyBoard(2, 2), closest)
+}
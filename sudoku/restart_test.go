@@ -0,0 +1,55 @@
+package sudoku
+
+import (
+	"testing"
+	"time"
+	"sudoku-csp/solver"
+)
+
+func TestRestartsSolve(t *testing.T) {
+	board := NewBoardFromSolvedWithSeed(11, 3, 3, 24)
+
+	for _, policy := range []solver.RestartPolicy{
+		solver.LubyRestarts{Scale: 1},
+		solver.GeometricRestarts{First: 1, Factor: 1.5},
+	} {
+		bt := solver.New(
+			NewNetworkFromBoard(board),
+			solver.WithChecker(solver.BasicCheck{}),
+			solver.WithRestarts(policy),
+			solver.WithShuffledValues(),
+			solver.WithSeed(5),
+		)
+
+		if !bt.Solve(time.Minute) {
+			t.Fatalf("%T: no solution, stats %v", policy, bt.Stats)
+		}
+		assertSolved(t, board, NewBoardFromNetwork(bt.Network, 3, 3))
+
+		if bt.Stats.Restarts == 0 {
+			t.Errorf("%T: solved without restarting, stats %v", policy, bt.Stats)
+		}
+	}
+}
+
+func TestRestartsKeepUserLimits(t *testing.T) {
+	bt := solver.New(
+		NewNetworkFromBoard(NewEmptyBoard(4, 4)),
+		solver.WithChecker(solver.BasicCheck{}),
+		solver.WithRestarts(solver.LubyRestarts{Scale: 2}),
+		solver.WithLimits(solver.Limits{Backtracks: 50}),
+		solver.WithShuffledValues(),
+		solver.WithSeed(1),
+	)
+
+	if bt.Solve(time.Minute) {
+		t.Skip("solved within the limit, nothing to check")
+	}
+
+	if bt.Stats.Exhausted != solver.BacktrackLimit || bt.Stats.Backtracks != 50 {
+		t.Errorf("expected the user's backtrack limit to stop the search, stats %v", bt.Stats)
+	}
+	if bt.Limits.Backtracks != 50 {
+		t.Errorf("restart budgets leaked into the limits: %v", bt.Limits)
+	}
+}
@@ -0,0 +1,79 @@
+package sudoku
+
+import (
+	"testing"
+	"time"
+	"sudoku-csp/solver"
+)
+
+func TestSearchStepsToSolution(t *testing.T) {
+	board := puzzle9x9()
+	bt    := newTestSolver(board)
+
+	search := solver.NewSearch(bt)
+	steps  := 0
+
+	for result := search.Step(); result != solver.Solved; result = search.Step() {
+		if result == solver.Exhausted || result == solver.Stopped {
+			t.Fatalf("search ended without a solution: %v", result)
+		}
+
+		for _, decision := range search.Path() {
+			if decision.Variable.Assignment() != decision.Value {
+				t.Fatalf("path decision %v = %d isn't assigned", decision.Variable.Name, decision.Value)
+			}
+		}
+
+		steps++
+	}
+
+	assertSolved(t, board, NewBoardFromNetwork(bt.Network, 3, 3))
+
+	// Solve takes the same decisions
+	reference := newTestSolver(board)
+	reference.Solve(time.Minute)
+
+	if bt.Stats.Nodes != reference.Stats.Nodes || steps != bt.Stats.Nodes {
+		t.Errorf("stepping tried %d values in %d steps, Solve tried %d", bt.Stats.Nodes, steps, reference.Stats.Nodes)
+	}
+}
+
+func TestSearchPauseAndResume(t *testing.T) {
+	bt     := newTestSolver(NewEmptyBoard(3, 3))
+	search := solver.NewSearch(bt)
+
+	search.Pause()
+	if result := search.Run(time.Minute); result != solver.Paused || search.Depth() != 1 {
+		t.Fatalf("expected a pause after the first decision, got %v at depth %d", result, search.Depth())
+	}
+
+	bt.Limits.Nodes = 10
+	if result := search.Run(time.Minute); result != solver.Stopped || bt.Stats.Exhausted != solver.NodeLimit {
+		t.Fatalf("expected the node limit to stop the search, got %v (%v)", result, bt.Stats)
+	}
+
+	bt.Limits.Nodes = 0
+	if result := search.Run(time.Minute); result != solver.Solved {
+		t.Fatalf("expected resuming to solve the board, got %v (%v)", result, bt.Stats)
+	}
+
+	assertSolved(t, nil, NewBoardFromNetwork(bt.Network, 3, 3))
+}
+
+func TestSearchRunsToExhaustion(t *testing.T) {
+	bt     := newTestSolver(NewEmptyBoard(2, 2))
+	search := solver.NewSearch(bt)
+
+	count := 0
+	for search.Run(time.Minute) == solver.Solved {
+		count++
+	}
+
+	if count != 288 || search.Result() != solver.Exhausted || len(search.Path()) != 0 {
+		t.Errorf("expected 288 grids then exhaustion, got %d, %v with path %v", count, search.Result(), search.Path())
+	}
+
+	if bt.Trail.Size() != 0 {
+		t.Errorf("exhausted search left %d trail entries", bt.Trail.Size())
+	}
+}
@@ -0,0 +1,244 @@
+package sudoku
+
+import (
+	"fmt"
+	"math/big"
+	"slices"
+	"sudoku-csp/solver"
+)
+
+/*
+Symmetry breaking for empty and near-empty boards
+
+Relabeling the digits, or permuting rows in ways that keep the band structure,
+turns any solved grid into another one, so an empty board's search space is
+made of huge classes of equivalent grids. BreakSymmetry adds constraints that
+keep exactly one grid of each class (its canonical form). On an empty board:
+
+  - the first row reads 1..N, fixing the relabeling
+  - within each band, rows below the fixed first row are ordered by their
+    first cell, ascending
+  - bands after the first are ordered by their top row's first cell
+
+Givens pin the rows and the digits they use, but relabeling the Free digits
+(those no given uses) among themselves still maps solutions to solutions. With
+givens only that symmetry is broken: the Free digits appear in ascending order
+along the first row.
+
+No grid is left unchanged by any of these symmetries, so every class has
+exactly OrbitSize grids: counting canonical grids and multiplying gives the full
+count, and Expand turns each canonical grid back into its whole class.
+*/
+type Symmetry struct {
+	BoxRows int
+	BoxCols int
+	Free    []int // digits no given uses, ascending
+	Rows    bool  // row permutations broken too, only without givens
+}
+
+// fails when a domain was narrowed beyond what the givens explain, which would break the relabeling too
+func BreakSymmetry(network *solver.Network, boxRows, boxCols int) (*Symmetry, error) {
+	boardLen := boxRows * boxCols
+	used     := make([]bool, boardLen + 1)
+
+	for _, variable := range network.Variables() {
+		if variable.Assigned() {
+			used[variable.Assignment()] = true
+		}
+	}
+
+	symmetry := &Symmetry{BoxRows: boxRows, BoxCols: boxCols}
+	for digit := 1; digit <= boardLen; digit++ {
+		if !used[digit] {
+			symmetry.Free = append(symmetry.Free, digit)
+		}
+	}
+
+	for _, variable := range network.Variables() {
+		if variable.Assigned() { continue }
+
+		for _, digit := range symmetry.Free {
+			if !slices.Contains(variable.Values(), digit) {
+				return nil, fmt.Errorf("symmetry breaking needs domains narrowed by givens only, %s lacks %d", variable.Name, digit)
+			}
+		}
+	}
+
+	firstRow := make([]*solver.Variable, boardLen)
+	for col := range firstRow {
+		firstRow[col] = VariableAt(network, 0, col)
+	}
+
+	if len(symmetry.Free) < boardLen {
+		if len(symmetry.Free) > 1 {
+			network.AddConstraint(solver.NewPrecedenceConstraint(firstRow, symmetry.Free))
+		}
+
+		return symmetry, nil
+	}
+
+	symmetry.Rows = true
+	first := func(row int) *solver.Variable { return VariableAt(network, row, 0) }
+
+	for col, variable := range firstRow {
+		network.AddConstraint(solver.NewEqualsConstraint(variable, col + 1))
+	}
+
+	for band := range boxCols {
+		top := band * boxRows
+		if band == 0 {
+			top = 1
+		}
+
+		for row := top; row < (band + 1) * boxRows - 1; row++ {
+			network.AddConstraint(solver.NewLessThanConstraint(first(row), first(row + 1)))
+		}
+
+		if band > 1 {
+			network.AddConstraint(solver.NewLessThanConstraint(first(top - boxRows), first(top)))
+		}
+	}
+
+	return symmetry, nil
+}
+
+// grids in each canonical grid's class: F! for F free digits, times (R-1)! * (R!)^(B-1) * (B-1)! with Rows
+func (s *Symmetry) OrbitSize() *big.Int {
+	size := factorial(len(s.Free))
+	if !s.Rows { return size }
+
+	size.Mul(size, factorial(s.BoxRows - 1))
+
+	for range s.BoxCols - 1 {
+		size.Mul(size, factorial(s.BoxRows))
+	}
+
+	return size.Mul(size, factorial(s.BoxCols - 1))
+}
+
+/*
+Visit every grid equivalent to the canonical board, the board itself included,
+until visit returns false. Returns false when visit stopped it.
+
+Each visited Board is fresh, keep it as long as needed.
+*/
+func (s *Symmetry) Expand(canonical *Board, visit func(*Board) bool) bool {
+	boardLen := s.BoxRows * s.BoxCols
+
+	// digits a given uses keep their label
+	relabel := make([]int, boardLen + 1)
+	for digit := range relabel {
+		relabel[digit] = digit
+	}
+
+	orders := [][]int{identityRows(boardLen)}
+	if s.Rows {
+		orders = s.rowOrders()
+	}
+
+	for _, order := range orders {
+		keepGoing := permute(s.Free, func(free []int) bool {
+			for index, digit := range s.Free {
+				relabel[digit] = free[index]
+			}
+
+			board := NewEmptyBoard(s.BoxRows, s.BoxCols)
+
+			for row, from := range order {
+				for col, value := range canonical.Cells[from] {
+					board.Cells[row][col] = relabel[value]
+				}
+			}
+
+			return visit(board)
+		})
+
+		if !keepGoing { return false }
+	}
+
+	return true
+}
+
+// Internal Helpers
+
+// every row permutation in the group, new row index -> old row index
+func (s *Symmetry) rowOrders() [][]int {
+	rows := func(band int) []int {
+		indices := make([]int, s.BoxRows)
+		for index := range indices {
+			indices[index] = band * s.BoxRows + index
+		}
+
+		return indices
+	}
+
+	// band 0 keeps its first row in place
+	orders := [][]int{}
+	permute(rows(0)[1:], func(rest []int) bool {
+		orders = append(orders, append([]int{0}, rest...))
+		return true
+	})
+
+	bands := make([]int, s.BoxCols - 1)
+	for index := range bands {
+		bands[index] = index + 1
+	}
+
+	all := [][]int{}
+	permute(bands, func(bandOrder []int) bool {
+		prefixes := orders
+
+		for _, band := range bandOrder {
+			extended := [][]int{}
+
+			permute(rows(band), func(inner []int) bool {
+				for _, prefix := range prefixes {
+					extended = append(extended, append(slices.Clone(prefix), inner...))
+				}
+				return true
+			})
+
+			prefixes = extended
+		}
+
+		all = append(all, prefixes...)
+		return true
+	})
+
+	return all
+}
+
+func identityRows(count int) []int {
+	rows := make([]int, count)
+	for row := range rows {
+		rows[row] = row
+	}
+
+	return rows
+}
+
+// visit every ordering of items (reusing one slice) until visit returns false
+func permute(items []int, visit func([]int) bool) bool {
+	current := slices.Clone(items)
+
+	var generate func(position int) bool
+	generate = func(position int) bool {
+		if position == len(current) { return visit(current) }
+
+		for index := position; index < len(current); index++ {
+			current[position], current[index] = current[index], current[position]
+			keepGoing := generate(position + 1)
+			current[position], current[index] = current[index], current[position]
+
+			if !keepGoing { return false }
+		}
+
+		return true
+	}
+
+	return generate(0)
+}
+
+func factorial(n int) *big.Int {
+	return new(big.Int).MulRange(1, int64(max(n, 1)))
+}
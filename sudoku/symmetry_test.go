@@ -0,0 +1,118 @@
+package sudoku
+
+import (
+	"math/big"
+	"testing"
+	"time"
+	"sudoku-csp/solver"
+)
+
+func TestSymmetryExpandsToEveryGrid(t *testing.T) {
+	bt := newTestSolver(NewEmptyBoard(2, 2))
+
+	symmetry, err := BreakSymmetry(bt.Network, 2, 2)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	seen := map[string]bool{}
+	canonical := bt.SolveAll(time.Minute, func() bool {
+		symmetry.Expand(NewBoardFromNetwork(bt.Network, 2, 2), func(grid *Board) bool {
+			assertSolved(t, nil, grid)
+			seen[grid.String()] = true
+			return true
+		})
+		return true
+	})
+
+	if canonical != 6 || len(seen) != 288 {
+		t.Errorf("expected 6 canonical grids expanding to 288, got %d expanding to %d", canonical, len(seen))
+	}
+}
+
+func TestSymmetryCounts6x6Grids(t *testing.T) {
+	// 28,200,960 grids whichever way the boxes lie
+	for _, shape := range [][2]int{{2, 3}, {3, 2}} {
+		bt := newTestSolver(NewEmptyBoard(shape[0], shape[1]))
+
+		symmetry, err := BreakSymmetry(bt.Network, shape[0], shape[1])
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		canonical := bt.SolveAll(time.Minute, nil)
+		total     := symmetry.OrbitSize()
+		total.Mul(total, big.NewInt(int64(canonical)))
+
+		if total.Int64() != 28200960 {
+			t.Errorf("%dx%d boxes: %d canonical grids * %v = %v", shape[0], shape[1], canonical, symmetry.OrbitSize(), total)
+		}
+	}
+}
+
+func TestSymmetryWithGivensKeepsFreeDigitRelabeling(t *testing.T) {
+	board := NewEmptyBoard(2, 2)
+	board.Cells[1][1] = 3
+
+	bt := newTestSolver(board)
+	symmetry, err := BreakSymmetry(bt.Network, 2, 2)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if symmetry.Rows || symmetry.OrbitSize().Int64() != 6 {
+		t.Fatalf("expected only the 3! relabelings of %v broken, orbit %v", symmetry.Free, symmetry.OrbitSize())
+	}
+
+	seen := map[string]bool{}
+	canonical := bt.SolveAll(time.Minute, func() bool {
+		symmetry.Expand(NewBoardFromNetwork(bt.Network, 2, 2), func(grid *Board) bool {
+			assertSolved(t, board, grid)
+			seen[grid.String()] = true
+			return true
+		})
+		return true
+	})
+
+	plain := newTestSolver(board)
+	every := map[string]bool{}
+	plain.SolveAll(time.Minute, func() bool {
+		every[NewBoardFromNetwork(plain.Network, 2, 2).String()] = true
+		return true
+	})
+
+	if canonical * 6 != len(every) || len(seen) != len(every) {
+		t.Errorf("%d canonical grids expanded to %d, plain enumeration found %d", canonical, len(seen), len(every))
+	}
+	for grid := range every {
+		if !seen[grid] {
+			t.Fatalf("expansion missed\n%v", grid)
+		}
+	}
+}
+
+func TestSymmetryRefusesNarrowedDomains(t *testing.T) {
+	network := NewNetworkFromBoard(NewEmptyBoard(2, 2))
+	VariableAt(network, 2, 3).RemoveValueFromDomain(4)
+
+	if _, err := BreakSymmetry(network, 2, 2); err == nil {
+		t.Error("expected a domain narrowed without a given to be refused")
+	}
+
+	// the canonical search still solves, with and without givens
+	nearEmpty := boardFromRows(3, 3, "....5....", ".1.......")
+	for _, board := range []*Board{NewEmptyBoard(3, 3), nearEmpty, puzzle9x9()} {
+		bt := newTestSolver(board)
+		if _, err := BreakSymmetry(bt.Network, 3, 3); err != nil {
+			t.Fatal(err)
+		}
+		if !bt.Solve(time.Minute) {
+			t.Fatal("canonical 9x9 grid not found")
+		}
+
+		assertSolved(t, board, NewBoardFromNetwork(bt.Network, 3, 3))
+		if violations := solver.Verify(bt.Network); len(violations) > 0 {
+			t.Errorf("symmetry constraints violated: %v", violations)
+		}
+	}
+}
@@ -0,0 +1,96 @@
+package sudoku
+
+import "fmt"
+
+type ViolationKind int
+
+const (
+	ShapeMismatch ViolationKind = iota
+	BlankCell
+	OutOfRange
+	DuplicateInRow
+	DuplicateInCol
+	DuplicateInBox
+	GivenChanged
+)
+
+func (k ViolationKind) String() string {
+	return [...]string{
+		"shape mismatch",
+		"blank cell",
+		"value out of range",
+		"duplicate in row",
+		"duplicate in col",
+		"duplicate in box",
+		"given changed",
+	}[k]
+}
+
+// one way a board fails to be a solution, Row/Col point at the offending cell
+type Violation struct {
+	Kind  ViolationKind
+	Row   int
+	Col   int
+	Value int
+}
+
+func (v Violation) String() string {
+	return fmt.Sprintf("%v at (%d, %d): %d", v.Kind, v.Row, v.Col, v.Value)
+}
+
+/*
+Checks the board as a full solution of original: every cell filled with a value
+in range, no repeats in any row, col or box, and every given of original kept.
+Works from the cells alone so it can vouch for any backend's output.
+*/
+func (b *Board) VerifySolution(original *Board) []Violation {
+	if original != nil && (original.BoxRows != b.BoxRows || original.BoxCols != b.BoxCols) {
+		return []Violation{{Kind: ShapeMismatch}}
+	}
+
+	violations := []Violation{}
+	boardLen   := b.boardLen
+
+	rowSeen := make([]map[int]bool, boardLen)
+	colSeen := make([]map[int]bool, boardLen)
+	boxSeen := make([]map[int]bool, boardLen)
+	for index := range boardLen {
+		rowSeen[index], colSeen[index], boxSeen[index] = map[int]bool{}, map[int]bool{}, map[int]bool{}
+	}
+
+	for row := range boardLen {
+		for col := range boardLen {
+			value := b.Cells[row][col]
+			box   := (row / b.BoxRows) * (boardLen / b.BoxCols) + col / b.BoxCols
+
+			if original != nil {
+				if given := original.Cells[row][col]; given != 0 && given != value {
+					violations = append(violations, Violation{GivenChanged, row, col, value})
+				}
+			}
+
+			if value == 0 {
+				violations = append(violations, Violation{BlankCell, row, col, value})
+				continue
+			}
+			if value < 1 || value > boardLen {
+				violations = append(violations, Violation{OutOfRange, row, col, value})
+				continue
+			}
+
+			if rowSeen[row][value] {
+				violations = append(violations, Violation{DuplicateInRow, row, col, value})
+			}
+			if colSeen[col][value] {
+				violations = append(violations, Violation{DuplicateInCol, row, col, value})
+			}
+			if boxSeen[box][value] {
+				violations = append(violations, Violation{DuplicateInBox, row, col, value})
+			}
+
+			rowSeen[row][value], colSeen[col][value], boxSeen[box][value] = true, true, true
+		}
+	}
+
+	return violations
+}
@@ -0,0 +1,62 @@
+package sudoku
+
+import (
+	"testing"
+	"time"
+	"sudoku-csp/solver"
+)
+
+func TestVerifySolution(t *testing.T) {
+	original := boardFromRows(2, 2,
+		"1...",
+		"....",
+		"....",
+		"...1",
+	)
+	solved := boardFromRows(2, 2,
+		"1234",
+		"3412",
+		"2143",
+		"4321",
+	)
+
+	if violations := solved.VerifySolution(original); len(violations) != 0 {
+		t.Fatalf("valid solution rejected: %v", violations)
+	}
+
+	// swap two cells of row 0 and blank one: a given is lost and both cols repeat
+	solved.Cells[0][0], solved.Cells[0][1] = 2, 1
+	solved.Cells[1][1] = 0
+
+	kinds := map[ViolationKind]int{}
+	for _, violation := range solved.VerifySolution(original) {
+		kinds[violation.Kind]++
+	}
+
+	expected := map[ViolationKind]int{GivenChanged: 1, BlankCell: 1, DuplicateInCol: 2, DuplicateInRow: 0, DuplicateInBox: 0}
+	for kind, count := range expected {
+		if kinds[kind] != count {
+			t.Errorf("expected %d %v, got %d (%v)", count, kind, kinds[kind], kinds)
+		}
+	}
+
+	if violations := solved.VerifySolution(NewEmptyBoard(3, 3)); len(violations) != 1 || violations[0].Kind != ShapeMismatch {
+		t.Errorf("expected a shape mismatch, got %v", violations)
+	}
+}
+
+func TestVerifyNetwork(t *testing.T) {
+	network := NewNetworkFromBoard(puzzle9x9())
+	if violations := solver.Verify(network); len(violations) == 0 {
+		t.Fatal("unsolved network verified")
+	}
+
+	bt := solver.NewBacktrackSolver(network, solver.NewTrail(), solver.MRV{}, solver.DefaultValOrder{}, solver.ForwardChecking{})
+	if !bt.Solve(time.Minute) {
+		t.Fatal("puzzle not solved")
+	}
+
+	if violations := solver.Verify(network); len(violations) != 0 {
+		t.Errorf("solution rejected: %v", violations)
+	}
+}